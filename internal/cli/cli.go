@@ -0,0 +1,247 @@
+// Package cli implements gokill's command-line entry point: the interactive
+// TUI by default, plus non-interactive subcommands meant for scripts, CI
+// checks and remote shells.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/w31r4/gokill/internal/tui"
+)
+
+// Exit codes shared by all subcommands.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+	// exitNoMatch means the target or filter matched no process.
+	exitNoMatch = 3
+	// exitPartial means at least one target could not be acted upon.
+	exitPartial = 4
+	// exitPermission means every failed target failed with a permission error.
+	exitPermission = 5
+)
+
+// command is a single `gokill <name>` subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+func commands() []command {
+	return []command{
+		{name: "list", summary: "print processes as a table, JSON or NDJSON", run: runList},
+		{name: "kill", summary: "signal processes selected by port, name, user, pid or search", run: runKill},
+		{name: "port", summary: "free a port: terminate its listeners and wait until it is released", run: runPort},
+		{name: "tree", summary: "print the process tree as text, JSON, DOT or Mermaid", run: runTree},
+		{name: "watch", summary: "stream process start/exit and port changes as NDJSON", run: runWatch},
+		{name: "why", summary: "explain why a process (pid, :port or name) is running", run: runWhy},
+	}
+}
+
+// Run dispatches args (without the program name) and returns the process exit code.
+// Arguments that do not name a subcommand are parsed as root flags, and the
+// remaining positional arguments are joined into the initial TUI search.
+func Run(args []string) int {
+	return run(args, os.Stdout, os.Stderr)
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "-h", "--help", "help":
+			printUsage(stdout)
+			return exitOK
+		}
+		for _, cmd := range commands() {
+			if cmd.name == args[0] {
+				return cmd.run(args[1:], stdout, stderr)
+			}
+		}
+	}
+	return runTUI(args, stderr)
+}
+
+// rootFlags are the flags accepted by `gokill [flags] [search...]`. Each one
+// seeds a piece of the initial TUI state.
+type rootFlags struct {
+	portsOnly      bool
+	tree           int
+	details        int
+	user           string
+	verboseDetails bool
+	refresh        time.Duration
+	grace          time.Duration
+	noEscalate     bool
+	scan           *scanFlags
+}
+
+func newRootFlagSet(stderr io.Writer) (*flag.FlagSet, *rootFlags) {
+	fs := flag.NewFlagSet("gokill", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { printUsage(stderr) }
+
+	f := &rootFlags{}
+	fs.BoolVar(&f.portsOnly, "ports-only", false, "start in ports-only mode (same as pressing P)")
+	fs.IntVar(&f.tree, "tree", 0, "open the dependency tree rooted at `pid` (same as pressing T)")
+	fs.IntVar(&f.details, "details", 0, "open the details view for `pid` (same as pressing i)")
+	fs.StringVar(&f.user, "user", "", "only show processes owned by `name`")
+	fs.BoolVar(&f.verboseDetails, "verbose-details", false, "open details in verbose mode (same as pressing v)")
+	fs.DurationVar(&f.refresh, "refresh", 0, "refresh the list automatically at this `interval`, e.g. 2s (0 = off; R toggles)")
+	fs.DurationVar(&f.grace, "grace", 3*time.Second, "how long a killed process gets to exit after SIGTERM before SIGKILL")
+	fs.BoolVar(&f.noEscalate, "no-escalate", false, "never send SIGKILL after the grace period; only report processes that survive")
+	f.scan = addScanFlags(fs)
+	return fs, f
+}
+
+func runTUI(args []string, stderr io.Writer) int {
+	fs, f := newRootFlagSet(stderr)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return parseExitCode(err)
+	}
+	if f.tree < 0 || f.details < 0 {
+		fmt.Fprintln(stderr, "gokill: --tree and --details need a positive pid")
+		return exitUsage
+	}
+	if f.refresh < 0 || (f.refresh > 0 && f.refresh < 100*time.Millisecond) {
+		fmt.Fprintln(stderr, "gokill: --refresh must be at least 100ms")
+		return exitUsage
+	}
+	if f.grace < 100*time.Millisecond {
+		fmt.Fprintln(stderr, "gokill: --grace must be at least 100ms")
+		return exitUsage
+	}
+	if err := f.scan.apply(); err != nil {
+		fmt.Fprintf(stderr, "gokill: %v\n", err)
+		return exitUsage
+	}
+
+	err = tui.Start(tui.Options{
+		Filter:          strings.Join(positional, " "),
+		PortsOnly:       f.portsOnly,
+		User:            f.user,
+		TreePID:         int32(f.tree),
+		DetailsPID:      int32(f.details),
+		VerboseDetails:  f.verboseDetails,
+		RefreshInterval: f.refresh,
+		KillGrace:       f.grace,
+		NoEscalate:      f.noEscalate,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  gokill [flags] [search...]  open the interactive process list")
+	fmt.Fprintln(w, "  gokill <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fs, _ := newRootFlagSet(w)
+	fs.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'gokill <command> -h' for command flags.")
+}
+
+// scanFlags are the process collection toggles shared by the TUI and every
+// subcommand. They map onto the GOKILL_SCAN_PORTS and GOKILL_PORT_TIMEOUT_MS
+// environment variables, which the process package reads.
+type scanFlags struct {
+	noPortScan  bool
+	portTimeout time.Duration
+}
+
+func addScanFlags(fs *flag.FlagSet) *scanFlags {
+	f := &scanFlags{}
+	fs.BoolVar(&f.noPortScan, "no-port-scan", false, "skip listening port collection (GOKILL_SCAN_PORTS=0)")
+	fs.DurationVar(&f.portTimeout, "port-timeout", 0, "per-process port scan timeout, e.g. 500ms (GOKILL_PORT_TIMEOUT_MS)")
+	return f
+}
+
+// apply exports the flags that were set; unset flags leave the environment alone.
+func (f *scanFlags) apply() error {
+	if f.portTimeout < 0 || (f.portTimeout > 0 && f.portTimeout < time.Millisecond) {
+		return fmt.Errorf("--port-timeout must be at least 1ms")
+	}
+	if f.noPortScan {
+		if err := os.Setenv("GOKILL_SCAN_PORTS", "0"); err != nil {
+			return err
+		}
+	}
+	if f.portTimeout > 0 {
+		if err := os.Setenv("GOKILL_PORT_TIMEOUT_MS", strconv.FormatInt(f.portTimeout.Milliseconds(), 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newFlagSet creates a flag set whose errors and usage go to stderr.
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: gokill %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args allowing flags and positional arguments to be mixed,
+// e.g. `gokill list node --format json`. Everything after "--" is positional.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for i, arg := range args {
+		if arg == "--" {
+			rest = args[i+1:]
+			args = args[:i]
+			break
+		}
+	}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	return append(positional, rest...), nil
+}
+
+// parseExitCode maps a flag parsing error to an exit code; -h is not a failure.
+func parseExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	return exitUsage
+}
+
+// printWarnings reports non-fatal collection errors on stderr.
+func printWarnings(stderr io.Writer, warnings []error) {
+	for _, w := range warnings {
+		fmt.Fprintf(stderr, "gokill: warning: %v\n", w)
+	}
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestScanFlagsExportEnvironment(t *testing.T) {
+	t.Setenv("GOKILL_SCAN_PORTS", "")
+	t.Setenv("GOKILL_PORT_TIMEOUT_MS", "")
+
+	fs := newFlagSet("test", "", io.Discard)
+	scan := addScanFlags(fs)
+	if _, err := parseFlags(fs, []string{"--no-port-scan", "--port-timeout", "1.5s"}); err != nil {
+		t.Fatalf("parseFlags: %v", err)
+	}
+	if err := scan.apply(); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if got := os.Getenv("GOKILL_SCAN_PORTS"); got != "0" {
+		t.Fatalf("GOKILL_SCAN_PORTS = %q", got)
+	}
+	if got := os.Getenv("GOKILL_PORT_TIMEOUT_MS"); got != "1500" {
+		t.Fatalf("GOKILL_PORT_TIMEOUT_MS = %q", got)
+	}
+}
+
+func TestScanFlagsRejectSubMillisecondTimeout(t *testing.T) {
+	fs := newFlagSet("test", "", io.Discard)
+	scan := addScanFlags(fs)
+	if _, err := parseFlags(fs, []string{"--port-timeout", "10us"}); err != nil {
+		t.Fatalf("parseFlags: %v", err)
+	}
+	if err := scan.apply(); err == nil {
+		t.Fatalf("expected sub-millisecond timeout to be rejected")
+	}
+}
+
+func TestRootFlagsParseWithSearch(t *testing.T) {
+	fs, f := newRootFlagSet(io.Discard)
+	positional, err := parseFlags(fs, []string{"--ports-only", "8080", "--tree", "12", "--user", "alice"})
+	if err != nil {
+		t.Fatalf("parseFlags: %v", err)
+	}
+	if !f.portsOnly || f.tree != 12 || f.user != "alice" {
+		t.Fatalf("unexpected flags: %+v", f)
+	}
+	if len(positional) != 1 || positional[0] != "8080" {
+		t.Fatalf("positional = %q", positional)
+	}
+}
+
+func TestRunRejectsUnknownRootFlag(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run([]string{"--bogus"}, io.Discard, &stderr); code != exitUsage {
+		t.Fatalf("exit code = %d, want %d", code, exitUsage)
+	}
+}
+
+func TestRunRejectsTooFastRefresh(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run([]string{"--refresh", "10ms"}, io.Discard, &stderr); code != exitUsage {
+		t.Fatalf("exit code = %d, want %d", code, exitUsage)
+	}
+}
+
+func TestRunRejectsTooShortGrace(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run([]string{"--grace", "0s"}, io.Discard, &stderr); code != exitUsage {
+		t.Fatalf("exit code = %d, want %d", code, exitUsage)
+	}
+}
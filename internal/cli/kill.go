@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/mattn/go-isatty"
+	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/search"
+)
+
+// stdin and stdinIsTerminal are variables so tests can script the confirmation prompt.
+var (
+	stdin           io.Reader = os.Stdin
+	stdinIsTerminal           = func() bool { return isatty.IsTerminal(os.Stdin.Fd()) }
+)
+
+// listFlag is a repeatable flag that also accepts comma separated values,
+// so `--port 3000,3001` and `--port 3000 --port 3001` are equivalent.
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(v string) error {
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+// killSelector describes which processes `gokill kill` targets. Every non-empty
+// criterion must match; values within one criterion are alternatives.
+type killSelector struct {
+	pids  []int32
+	ports []uint32
+	names []string
+	users []string
+	query string
+}
+
+func (s killSelector) empty() bool {
+	return len(s.pids) == 0 && len(s.ports) == 0 && len(s.names) == 0 && len(s.users) == 0 && s.query == ""
+}
+
+// match returns the selected items, never including gokill itself. An invalid
+// query term is an error rather than being dropped from the query.
+func (s killSelector) match(items []*process.Item, self int32) ([]*process.Item, error) {
+	candidates, err := search.FilterQuery(items, search.Options{Query: s.query})
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*process.Item
+	for _, it := range candidates {
+		if it.Pid == self {
+			continue
+		}
+		if len(s.pids) > 0 && !containsPID(s.pids, it.Pid) {
+			continue
+		}
+		if len(s.ports) > 0 && !hasAnyPort(it, s.ports) {
+			continue
+		}
+		if len(s.names) > 0 && !matchesAnyName(it, s.names) {
+			continue
+		}
+		if len(s.users) > 0 && !matchesAnyUser(it, s.users) {
+			continue
+		}
+		out = append(out, it)
+	}
+	return out, nil
+}
+
+func containsPID(pids []int32, pid int32) bool {
+	for _, p := range pids {
+		if p == pid {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPort(it *process.Item, ports []uint32) bool {
+	for _, want := range ports {
+		if hasPort(it, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyName(it *process.Item, names []string) bool {
+	for _, name := range names {
+		if nameMatches(it, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyUser(it *process.Item, users []string) bool {
+	for _, u := range users {
+		if strings.EqualFold(it.User, u) {
+			return true
+		}
+	}
+	return false
+}
+
+// killResult records what happened to one target.
+type killResult struct {
+	item   *process.Item
+	action string
+	err    error
+}
+
+// killAction describes how a target is stopped: docker-proxy entries go
+// through `docker stop`, everything else receives the signal directly.
+func killAction(it *process.Item, sig syscall.Signal) string {
+	if it.ContainerName != "" {
+		return "docker stop " + it.ContainerName
+	}
+	return process.SignalName(sig)
+}
+
+func applyKill(items []*process.Item, sig syscall.Signal) []killResult {
+	results := make([]killResult, 0, len(items))
+	for _, it := range items {
+		var err error
+		if it.ContainerName != "" {
+			err = process.StopContainer(it.ContainerName)
+		} else {
+			err = process.SignalItem(it, sig)
+		}
+		results = append(results, killResult{item: it, action: killAction(it, sig), err: err})
+	}
+	return results
+}
+
+// killExitCode maps per-target results to the documented exit codes:
+// exitPartial only when some targets succeeded and others failed.
+func killExitCode(results []killResult) int {
+	failed, denied := 0, 0
+	for _, r := range results {
+		if r.err == nil {
+			continue
+		}
+		failed++
+		if errors.Is(r.err, os.ErrPermission) {
+			denied++
+		}
+	}
+	switch {
+	case failed == 0:
+		return exitOK
+	case failed == len(results) && denied == failed:
+		return exitPermission
+	case failed == len(results):
+		return exitError
+	default:
+		return exitPartial
+	}
+}
+
+func runKill(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("kill", "[flags] [search...]", stderr)
+	var pidArgs, portArgs, names, users listFlag
+	fs.Var(&pidArgs, "pid", "target PID (repeatable, comma separated)")
+	fs.Var(&portArgs, "port", "target processes listening on this port (repeatable, comma separated)")
+	fs.Var(&names, "name", "target processes with this exact executable or container name (repeatable)")
+	fs.Var(&users, "user", "target processes owned by this user (repeatable)")
+	sigName := fs.String("signal", "TERM", "signal to send, by name (TERM, KILL, HUP, ...) or number")
+	dryRun := fs.Bool("dry-run", false, "show what would be signalled without sending anything")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	scan := addScanFlags(fs)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return parseExitCode(err)
+	}
+	if err := scan.apply(); err != nil {
+		fmt.Fprintf(stderr, "gokill kill: %v\n", err)
+		return exitUsage
+	}
+
+	sel := killSelector{
+		names: names,
+		users: users,
+		query: strings.Join(positional, " "),
+	}
+	for _, v := range pidArgs {
+		pid, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || pid <= 0 {
+			fmt.Fprintf(stderr, "gokill kill: invalid pid %q\n", v)
+			return exitUsage
+		}
+		sel.pids = append(sel.pids, int32(pid))
+	}
+	for _, v := range portArgs {
+		port, err := parsePort(v)
+		if err != nil {
+			fmt.Fprintf(stderr, "gokill kill: %v\n", err)
+			return exitUsage
+		}
+		sel.ports = append(sel.ports, port)
+	}
+	if sel.empty() {
+		fmt.Fprintln(stderr, "gokill kill: refusing to run without --pid, --port, --name, --user or a search term")
+		return exitUsage
+	}
+	if _, err := search.ParseQuery(sel.query); err != nil {
+		fmt.Fprintf(stderr, "gokill kill: invalid search: %v\n", err)
+		return exitUsage
+	}
+	if len(sel.ports) > 0 && !process.PortScanningEnabled() {
+		fmt.Fprintln(stderr, "gokill kill: --port needs port scanning, which is disabled by GOKILL_SCAN_PORTS")
+		return exitUsage
+	}
+	sig, err := process.ParseSignal(*sigName)
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill kill: %v\n", err)
+		return exitUsage
+	}
+
+	items, _, err := process.GetProcesses()
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill kill: %v\n", err)
+		return exitError
+	}
+	targets, err := sel.match(items, int32(os.Getpid()))
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill kill: invalid search: %v\n", err)
+		return exitUsage
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(stderr, "gokill kill: no process matched")
+		return exitNoMatch
+	}
+
+	if err := writeItemsTable(stdout, targets); err != nil {
+		fmt.Fprintf(stderr, "gokill kill: %v\n", err)
+		return exitError
+	}
+
+	if *dryRun {
+		fmt.Fprintln(stdout, "\ndry run, nothing was sent:")
+		for _, it := range targets {
+			fmt.Fprintf(stdout, "would   %s (pid %d): %s\n", it.Executable, it.Pid, killAction(it, sig))
+		}
+		return exitOK
+	}
+
+	if !*yes {
+		if !stdinIsTerminal() {
+			fmt.Fprintln(stderr, "gokill kill: stdin is not a terminal; pass --yes to confirm")
+			return exitUsage
+		}
+		fmt.Fprintf(stdout, "\nSend %s to %s? [y/N] ", process.SignalName(sig), plural(len(targets), "process", "processes"))
+		if !readConfirmation(stdin) {
+			fmt.Fprintln(stdout, "aborted")
+			return exitError
+		}
+	}
+
+	results := applyKill(targets, sig)
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(stderr, "failed  %s (pid %d): %s: %v\n", r.item.Executable, r.item.Pid, r.action, r.err)
+			continue
+		}
+		fmt.Fprintf(stdout, "ok      %s (pid %d): %s\n", r.item.Executable, r.item.Pid, r.action)
+	}
+	return killExitCode(results)
+}
+
+func readConfirmation(r io.Reader) bool {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && line == "" {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+func TestListFlagAcceptsRepeatsAndCommas(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var ports listFlag
+	fs.Var(&ports, "port", "")
+	if err := fs.Parse([]string{"--port", "3000, 3001", "--port", "8080"}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := ports.String(); got != "3000,3001,8080" {
+		t.Fatalf("ports = %q", got)
+	}
+}
+
+func TestKillSelectorMatch(t *testing.T) {
+	self := process.NewItem(99, "gokill", "alice")
+	web := process.NewItem(10, "node", "alice", 3000)
+	worker := process.NewItem(11, "node", "bob")
+	db := process.NewItem(12, "postgres", "postgres", 5432)
+	items := []*process.Item{self, web, worker, db}
+
+	cases := []struct {
+		name string
+		sel  killSelector
+		want []int32
+	}{
+		{"by port", killSelector{ports: []uint32{3000, 5432}}, []int32{10, 12}},
+		{"by name and user", killSelector{names: []string{"NODE"}, users: []string{"bob"}}, []int32{11}},
+		{"by pid", killSelector{pids: []int32{12}}, []int32{12}},
+		{"never self", killSelector{pids: []int32{99}}, nil},
+		{"fuzzy query", killSelector{query: "postgres"}, []int32{12}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.sel.match(items, 99)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pids []int32
+			for _, it := range got {
+				pids = append(pids, it.Pid)
+			}
+			if len(pids) != len(tc.want) {
+				t.Fatalf("got pids %v, want %v", pids, tc.want)
+			}
+			for i := range pids {
+				if pids[i] != tc.want[i] {
+					t.Fatalf("got pids %v, want %v", pids, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidSearchTermIsAUsageError(t *testing.T) {
+	if _, err := (killSelector{query: "cpu>abc"}).match([]*process.Item{process.NewItem(1, "a", "test")}, 99); err == nil {
+		t.Fatal("expected match to reject cpu>abc instead of matching everything")
+	}
+	for _, args := range [][]string{{"kill", "--dry-run", "cpu>abc"}, {"list", "port:80x"}} {
+		var stderr bytes.Buffer
+		if code := run(args, io.Discard, &stderr); code != exitUsage || !strings.Contains(stderr.String(), "invalid search") {
+			t.Fatalf("%v: exit code = %d, stderr %q", args, code, stderr.String())
+		}
+	}
+}
+
+func TestKillExitCode(t *testing.T) {
+	denied := &os.SyscallError{Syscall: "kill", Err: syscall.EPERM}
+	gone := errors.New("process already finished")
+	it := process.NewItem(1, "x", "u")
+
+	cases := []struct {
+		name    string
+		results []killResult
+		want    int
+	}{
+		{"all ok", []killResult{{item: it}, {item: it}}, exitOK},
+		{"all denied", []killResult{{item: it, err: denied}}, exitPermission},
+		{"some denied", []killResult{{item: it}, {item: it, err: denied}}, exitPartial},
+		{"other failure", []killResult{{item: it, err: gone}}, exitError},
+		{"some failed", []killResult{{item: it}, {item: it, err: gone}}, exitPartial},
+		{"all failed, some denied", []killResult{{item: it, err: denied}, {item: it, err: gone}}, exitError},
+	}
+	for _, tc := range cases {
+		if got := killExitCode(tc.results); got != tc.want {
+			t.Fatalf("%s: killExitCode = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestReadConfirmation(t *testing.T) {
+	for in, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "": false} {
+		if got := readConfirmation(strings.NewReader(in)); got != want {
+			t.Fatalf("readConfirmation(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestKillActionUsesDockerStopForContainers(t *testing.T) {
+	it := process.NewItem(5, "docker-proxy", "root", 8080)
+	it.ContainerName = "api"
+	if got := killAction(it, syscall.SIGTERM); got != "docker stop api" {
+		t.Fatalf("killAction = %q", got)
+	}
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/search"
+)
+
+// Output formats understood by the list-style commands.
+const (
+	formatTable  = "table"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
+func runList(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("list", "[flags] [search...]", stderr)
+	format := fs.String("format", formatTable, "output format: table, json or ndjson")
+	portsOnly := fs.Bool("ports-only", false, "only show processes listening on ports, sorted by port")
+	quiet := fs.Bool("quiet", false, "do not print collection warnings to stderr")
+	scan := addScanFlags(fs)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return parseExitCode(err)
+	}
+	if err := scan.apply(); err != nil {
+		fmt.Fprintf(stderr, "gokill list: %v\n", err)
+		return exitUsage
+	}
+	if !validFormat(*format) {
+		fmt.Fprintf(stderr, "gokill list: unknown format %q\n", *format)
+		return exitUsage
+	}
+	query := strings.Join(positional, " ")
+	if _, err := search.ParseQuery(query); err != nil {
+		fmt.Fprintf(stderr, "gokill list: invalid search: %v\n", err)
+		return exitUsage
+	}
+
+	items, warnings, err := process.GetProcesses()
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill list: %v\n", err)
+		return exitError
+	}
+	if !*quiet {
+		printWarnings(stderr, warnings)
+	}
+
+	filtered, err := search.FilterQuery(items, search.Options{
+		Query:     query,
+		PortsOnly: *portsOnly,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill list: invalid search: %v\n", err)
+		return exitUsage
+	}
+	if err := writeItems(stdout, filtered, *format); err != nil {
+		fmt.Fprintf(stderr, "gokill list: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+func validFormat(format string) bool {
+	switch format {
+	case formatTable, formatJSON, formatNDJSON:
+		return true
+	}
+	return false
+}
+
+// writeItems renders items in the requested format.
+func writeItems(w io.Writer, items []*process.Item, format string) error {
+	switch format {
+	case formatJSON:
+		if items == nil {
+			items = []*process.Item{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	case formatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, it := range items {
+			if err := enc.Encode(it); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return writeItemsTable(w, items)
+	}
+}
+
+func writeItemsTable(w io.Writer, items []*process.Item) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PID\tPPID\tUSER\tSTART\tNAME\tPORTS")
+	now := time.Now()
+	for _, it := range items {
+		name := it.Executable
+		if it.ContainerName != "" {
+			name = fmt.Sprintf("%s [%s]", it.Executable, it.ContainerName)
+		}
+		ports := strings.ReplaceAll(search.PortsString(it.Ports), " ", ",")
+		if ports == "" {
+			ports = "-"
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%s\n", it.Pid, it.PPid, it.User, process.FormatStartTime(it.StartTime, now), name, ports)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+func TestParseFlagsInterspersed(t *testing.T) {
+	fs := newFlagSet("list", "", io.Discard)
+	format := fs.String("format", formatTable, "")
+	portsOnly := fs.Bool("ports-only", false, "")
+
+	positional, err := parseFlags(fs, []string{"node", "--format", "json", "server", "--ports-only", "--", "--literal"})
+	if err != nil {
+		t.Fatalf("parseFlags returned error: %v", err)
+	}
+	if *format != formatJSON || !*portsOnly {
+		t.Fatalf("flags not parsed: format=%q portsOnly=%v", *format, *portsOnly)
+	}
+	want := []string{"node", "server", "--literal"}
+	if strings.Join(positional, ",") != strings.Join(want, ",") {
+		t.Fatalf("positional = %q, want %q", positional, want)
+	}
+}
+
+func TestWriteItemsJSONEmptyIsArray(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeItems(&buf, nil, formatJSON); err != nil {
+		t.Fatalf("writeItems: %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Fatalf("expected empty JSON array, got %q", got)
+	}
+}
+
+func TestWriteItemsNDJSON(t *testing.T) {
+	items := []*process.Item{
+		process.NewItem(10, "node", "alice", 3000),
+		process.NewItem(11, "redis-server", "redis", 6379),
+	}
+
+	var buf bytes.Buffer
+	if err := writeItems(&buf, items, formatNDJSON); err != nil {
+		t.Fatalf("writeItems: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 NDJSON lines, got %d: %q", len(lines), buf.String())
+	}
+	var decoded process.Item
+	if err := json.Unmarshal([]byte(lines[1]), &decoded); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+	if decoded.Pid != 11 || decoded.Executable != "redis-server" || len(decoded.Ports) != 1 || decoded.Ports[0] != 6379 {
+		t.Fatalf("unexpected decoded item: %#v", decoded)
+	}
+}
+
+func TestWriteItemsTable(t *testing.T) {
+	items := []*process.Item{
+		process.NewItem(10, "node", "alice", 3000, 3001),
+		process.NewItem(11, "sleep", "bob"),
+	}
+
+	var buf bytes.Buffer
+	if err := writeItems(&buf, items, formatTable); err != nil {
+		t.Fatalf("writeItems: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "PID") {
+		t.Fatalf("expected header row, got:\n%s", out)
+	}
+	if !strings.Contains(out, "3000,3001") {
+		t.Fatalf("expected comma separated ports, got:\n%s", out)
+	}
+	if !strings.Contains(out, "sleep") || !strings.Contains(out, " -") {
+		t.Fatalf("expected placeholder for missing ports, got:\n%s", out)
+	}
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// portFreeReport is the JSON record printed by `gokill port free --format json`.
+type portFreeReport struct {
+	Port      uint32           `json:"port"`
+	Freed     bool             `json:"freed"`
+	Holders   []portFreeHolder `json:"holders"`
+	Respawned []*process.Item  `json:"respawned,omitempty"`
+	ElapsedMS int64            `json:"elapsedMs"`
+}
+
+type portFreeHolder struct {
+	Process *process.Item `json:"process"`
+	Action  string        `json:"action"`
+	Error   string        `json:"error,omitempty"`
+}
+
+func runPort(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "free" {
+		fmt.Fprintln(stderr, "Usage: gokill port free [flags] <port>")
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			return exitOK
+		}
+		return exitUsage
+	}
+	return runPortFree(args[1:], stdout, stderr)
+}
+
+func runPortFree(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("port free", "[flags] <port>", stderr)
+	wait := fs.Bool("wait", false, "escalate to SIGKILL after --grace and wait until the port is released")
+	grace := fs.Duration("grace", 3*time.Second, "time listeners get to exit after SIGTERM (with --wait)")
+	timeout := fs.Duration("timeout", 10*time.Second, "how long to wait for the port to be released (with --wait)")
+	format := fs.String("format", formatText, "output format: text or json")
+	dryRun := fs.Bool("dry-run", false, "only show which processes hold the port")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	scan := addScanFlags(fs)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return parseExitCode(err)
+	}
+	if err := scan.apply(); err != nil {
+		fmt.Fprintf(stderr, "gokill port free: %v\n", err)
+		return exitUsage
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	if !process.PortScanningEnabled() {
+		fmt.Fprintln(stderr, "gokill port free: needs port scanning, which is disabled by GOKILL_SCAN_PORTS")
+		return exitUsage
+	}
+	port, err := parsePort(positional[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill port free: %v\n", err)
+		return exitUsage
+	}
+	if *format != formatText && *format != formatJSON {
+		fmt.Fprintf(stderr, "gokill port free: unknown format %q\n", *format)
+		return exitUsage
+	}
+	if *grace <= 0 || *timeout <= 0 {
+		fmt.Fprintln(stderr, "gokill port free: --grace and --timeout must be positive")
+		return exitUsage
+	}
+
+	ctx := context.Background()
+	holders, err := process.PortListeners(ctx, port)
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill port free: %v\n", err)
+		return exitError
+	}
+	if len(holders) == 0 {
+		// The goal is a free port, so an unbound port is a success.
+		return writePortFree(stdout, stderr, &process.FreePortResult{Port: port, Freed: true}, *format)
+	}
+
+	if *format == formatText {
+		if err := writeItemsTable(stdout, holders); err != nil {
+			fmt.Fprintf(stderr, "gokill port free: %v\n", err)
+			return exitError
+		}
+	}
+	if *dryRun {
+		if *format == formatJSON {
+			return writePortFree(stdout, stderr, &process.FreePortResult{Port: port, Holders: dryRunHolders(holders)}, *format)
+		}
+		fmt.Fprintf(stdout, "\ndry run: would free port %d held by %s\n", port, plural(len(holders), "process", "processes"))
+		return exitOK
+	}
+	if !*yes {
+		if !stdinIsTerminal() {
+			fmt.Fprintln(stderr, "gokill port free: stdin is not a terminal; pass --yes to confirm")
+			return exitUsage
+		}
+		fmt.Fprintf(stdout, "\nFree port %d by terminating %s? [y/N] ", port, plural(len(holders), "process", "processes"))
+		if !readConfirmation(stdin) {
+			fmt.Fprintln(stdout, "aborted")
+			return exitError
+		}
+	}
+
+	res, err := process.FreePort(ctx, port, process.FreePortOptions{Wait: *wait, Grace: *grace, Timeout: *timeout, Holders: holders})
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill port free: %v\n", err)
+		return exitError
+	}
+	if code := writePortFree(stdout, stderr, res, *format); code != exitOK {
+		return code
+	}
+	return portFreeExitCode(res, *wait)
+}
+
+func dryRunHolders(items []*process.Item) []process.PortHolder {
+	holders := make([]process.PortHolder, len(items))
+	for i, it := range items {
+		holders[i] = process.PortHolder{Item: it}
+	}
+	return holders
+}
+
+// portFreeExitCode maps the result onto the shared exit codes. Without
+// --wait only the signals are checked, since nobody waited for the port.
+func portFreeExitCode(res *process.FreePortResult, waited bool) int {
+	failed, denied := 0, 0
+	for _, h := range res.Holders {
+		if h.Err != nil {
+			failed++
+			if errors.Is(h.Err, os.ErrPermission) {
+				denied++
+			}
+		}
+	}
+	switch {
+	case len(res.Holders) > 0 && denied == len(res.Holders):
+		return exitPermission
+	case waited && !res.Freed, failed > 0:
+		return exitPartial
+	}
+	return exitOK
+}
+
+func writePortFree(stdout, stderr io.Writer, res *process.FreePortResult, format string) int {
+	if format == formatJSON {
+		report := portFreeReport{
+			Port:      res.Port,
+			Freed:     res.Freed,
+			Holders:   []portFreeHolder{},
+			Respawned: res.Respawned,
+			ElapsedMS: res.Elapsed.Milliseconds(),
+		}
+		for _, h := range res.Holders {
+			holder := portFreeHolder{Process: h.Item, Action: h.Action}
+			if h.Err != nil {
+				holder.Error = h.Err.Error()
+			}
+			report.Holders = append(report.Holders, holder)
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "gokill port free: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	if len(res.Holders) > 0 {
+		fmt.Fprintln(stdout)
+	}
+	for _, h := range res.Holders {
+		if h.Err != nil {
+			fmt.Fprintf(stderr, "failed  %s (pid %d): %s: %v\n", h.Item.Executable, h.Item.Pid, h.Action, h.Err)
+			continue
+		}
+		fmt.Fprintf(stdout, "ok      %s (pid %d): %s\n", h.Item.Executable, h.Item.Pid, h.Action)
+	}
+	fmt.Fprintln(stdout, res.Summary())
+	return exitOK
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+func TestPortFreeExitCode(t *testing.T) {
+	it := process.NewItem(1, "node", "dev", 3000)
+	denied := &os.SyscallError{Syscall: "kill", Err: syscall.EPERM}
+
+	cases := []struct {
+		name   string
+		res    process.FreePortResult
+		waited bool
+		want   int
+	}{
+		{"nothing listening", process.FreePortResult{Freed: true}, true, exitOK},
+		{"freed", process.FreePortResult{Freed: true, Holders: []process.PortHolder{{Item: it}}}, true, exitOK},
+		{"signalled without wait", process.FreePortResult{Holders: []process.PortHolder{{Item: it}}}, false, exitOK},
+		{"still bound", process.FreePortResult{Holders: []process.PortHolder{{Item: it}}}, true, exitPartial},
+		{"signal failed", process.FreePortResult{Holders: []process.PortHolder{{Item: it, Err: errors.New("boom")}}}, false, exitPartial},
+		{"denied", process.FreePortResult{Holders: []process.PortHolder{{Item: it, Err: denied}}}, true, exitPermission},
+	}
+	for _, tc := range cases {
+		if got := portFreeExitCode(&tc.res, tc.waited); got != tc.want {
+			t.Fatalf("%s: exit code = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
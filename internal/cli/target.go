@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// resolveTarget turns a target spec into the processes it names:
+//
+//   - "1234"  the process with that PID
+//   - ":8080" every process listening on that port
+//   - "nginx" every process whose executable or container name is "nginx"
+//
+// Only the PID form avoids a full process scan.
+func resolveTarget(spec string) ([]*process.Item, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("empty target")
+	}
+
+	if pid, err := strconv.ParseInt(spec, 10, 32); err == nil {
+		if pid <= 0 {
+			return nil, fmt.Errorf("invalid pid %d", pid)
+		}
+		item, _, err := process.GetProcess(int32(pid))
+		if err != nil {
+			return nil, err
+		}
+		return []*process.Item{item}, nil
+	}
+
+	if strings.HasPrefix(spec, ":") {
+		if _, err := parsePort(spec[1:]); err != nil {
+			return nil, err
+		}
+		if !process.PortScanningEnabled() {
+			return nil, fmt.Errorf("port lookup needs port scanning, which is disabled by GOKILL_SCAN_PORTS")
+		}
+	}
+
+	items, _, err := process.GetProcesses()
+	if err != nil {
+		return nil, err
+	}
+	return selectTargets(items, spec)
+}
+
+// selectTargets resolves a target spec against an existing process list.
+func selectTargets(items []*process.Item, spec string) ([]*process.Item, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("empty target")
+	}
+	if pid, err := strconv.ParseInt(spec, 10, 32); err == nil {
+		if pid <= 0 {
+			return nil, fmt.Errorf("invalid pid %d", pid)
+		}
+		return filterByPID(items, int32(pid)), nil
+	}
+	if strings.HasPrefix(spec, ":") {
+		port, err := parsePort(spec[1:])
+		if err != nil {
+			return nil, err
+		}
+		return filterByPort(items, port), nil
+	}
+	return filterByName(items, spec), nil
+}
+
+func parsePort(s string) (uint32, error) {
+	port, err := strconv.ParseUint(strings.TrimSpace(s), 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return uint32(port), nil
+}
+
+func filterByPID(items []*process.Item, pid int32) []*process.Item {
+	for _, it := range items {
+		if it.Pid == pid {
+			return []*process.Item{it}
+		}
+	}
+	return nil
+}
+
+func filterByPort(items []*process.Item, port uint32) []*process.Item {
+	var out []*process.Item
+	for _, it := range items {
+		if hasPort(it, port) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// filterByName matches executable or container names exactly, ignoring case.
+func filterByName(items []*process.Item, name string) []*process.Item {
+	var out []*process.Item
+	for _, it := range items {
+		if nameMatches(it, name) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+func hasPort(it *process.Item, port uint32) bool {
+	for _, p := range it.Ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+func nameMatches(it *process.Item, name string) bool {
+	return strings.EqualFold(it.Executable, name) || (it.ContainerName != "" && strings.EqualFold(it.ContainerName, name))
+}
@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/search"
+)
+
+// Graph formats understood by `gokill tree` in addition to text and json.
+const (
+	formatDOT     = "dot"
+	formatMermaid = "mermaid"
+)
+
+// treeReport is one printed tree: the root's ancestors (outermost first) and
+// the subtree below it.
+type treeReport struct {
+	Ancestors []*process.Item   `json:"ancestors,omitempty"`
+	Tree      *process.TreeNode `json:"tree"`
+}
+
+func runTree(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("tree", "[flags] [pid|:port|name]", stderr)
+	format := fs.String("format", formatText, "output format: text, json, dot or mermaid")
+	depth := fs.Int("depth", 0, "maximum depth below the root (0 = unlimited)")
+	aliveOnly := fs.Bool("alive-only", false, "only keep processes that are still running (no zombies)")
+	listeningOnly := fs.Bool("listening-only", false, "only keep processes listening on ports")
+	ancestors := fs.Bool("ancestors", false, "also print the ancestor chain above the root")
+	quiet := fs.Bool("quiet", false, "do not print collection warnings to stderr")
+	scan := addScanFlags(fs)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return parseExitCode(err)
+	}
+	if err := scan.apply(); err != nil {
+		fmt.Fprintf(stderr, "gokill tree: %v\n", err)
+		return exitUsage
+	}
+	if len(positional) > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	switch *format {
+	case formatText, formatJSON, formatDOT, formatMermaid:
+	default:
+		fmt.Fprintf(stderr, "gokill tree: unknown format %q\n", *format)
+		return exitUsage
+	}
+	if *depth < 0 {
+		fmt.Fprintln(stderr, "gokill tree: --depth must not be negative")
+		return exitUsage
+	}
+	if *listeningOnly && !process.PortScanningEnabled() {
+		fmt.Fprintln(stderr, "gokill tree: --listening-only needs port scanning, which is disabled by GOKILL_SCAN_PORTS")
+		return exitUsage
+	}
+
+	items, warnings, err := process.GetProcesses()
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill tree: %v\n", err)
+		return exitError
+	}
+	if !*quiet {
+		printWarnings(stderr, warnings)
+	}
+
+	roots := process.Roots(items)
+	if len(positional) == 1 {
+		roots, err = selectTargets(items, positional[0])
+		if err != nil {
+			fmt.Fprintf(stderr, "gokill tree: %v\n", err)
+			return exitUsage
+		}
+		if len(roots) == 0 {
+			fmt.Fprintf(stderr, "gokill tree: no process matches %q\n", positional[0])
+			return exitNoMatch
+		}
+	}
+
+	opts := process.TreeOptions{MaxDepth: *depth, Keep: treeFilter(*aliveOnly, *listeningOnly)}
+	reports := buildTreeReports(items, roots, opts, *ancestors)
+	if len(reports) == 0 {
+		fmt.Fprintln(stderr, "gokill tree: no process in the tree matches the filters")
+		return exitNoMatch
+	}
+
+	if err := writeTrees(stdout, reports, *format); err != nil {
+		fmt.Fprintf(stderr, "gokill tree: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// treeFilter returns the Keep predicate for the requested filters, or nil
+// when every process is kept.
+func treeFilter(aliveOnly, listeningOnly bool) func(*process.Item) bool {
+	if !aliveOnly && !listeningOnly {
+		return nil
+	}
+	return func(it *process.Item) bool {
+		if listeningOnly && len(it.Ports) == 0 {
+			return false
+		}
+		if aliveOnly && (it.Status != process.Alive || !process.IsRunning(it.Pid)) {
+			return false
+		}
+		return true
+	}
+}
+
+func buildTreeReports(items, roots []*process.Item, opts process.TreeOptions, withAncestors bool) []treeReport {
+	children := process.ChildrenMap(items)
+	var reports []treeReport
+	for _, root := range roots {
+		tree := process.BuildTree(root, children, opts)
+		if tree == nil {
+			continue
+		}
+		report := treeReport{Tree: tree}
+		if withAncestors {
+			chain := process.Ancestors(items, root, 0)
+			for i := len(chain) - 1; i >= 0; i-- {
+				report.Ancestors = append(report.Ancestors, chain[i])
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+func writeTrees(w io.Writer, reports []treeReport, format string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	case formatDOT:
+		return writeTreesDOT(w, reports)
+	case formatMermaid:
+		return writeTreesMermaid(w, reports)
+	default:
+		return writeTreesText(w, reports)
+	}
+}
+
+// treeLabel is the node text shared by every format: "name (pid)", followed
+// by the listening ports when there are any.
+func treeLabel(it *process.Item) string {
+	label := fmt.Sprintf("%s (%d)", it.Executable, it.Pid)
+	if len(it.Ports) > 0 {
+		label += " [" + strings.ReplaceAll(search.PortsString(it.Ports), " ", ",") + "]"
+	}
+	return label
+}
+
+// writeTreesText draws each tree with the same connectors as the TUI
+// dependency view.
+func writeTreesText(w io.Writer, reports []treeReport) error {
+	var b strings.Builder
+	for i, r := range reports {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if len(r.Ancestors) > 0 {
+			b.WriteString("Ancestors\n")
+			for depth, it := range r.Ancestors {
+				fmt.Fprintf(&b, "%s└─ %s\n", strings.Repeat("   ", depth), treeLabel(it))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(treeLabel(r.Tree.Process) + "\n")
+		writeTreeChildren(&b, r.Tree, "")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTreeChildren(b *strings.Builder, node *process.TreeNode, prefix string) {
+	if node.Truncated {
+		fmt.Fprintf(b, "%s└─ … (deeper)\n", prefix)
+		return
+	}
+	for i, child := range node.Children {
+		last := i == len(node.Children)-1
+		connector, next := "├─", prefix+"│  "
+		if last {
+			connector, next = "└─", prefix+"   "
+		}
+		fmt.Fprintf(b, "%s%s %s\n", prefix, connector, treeLabel(child.Process))
+		writeTreeChildren(b, child, next)
+	}
+}
+
+// walkTreeEdges calls node once per process and edge once per parent/child
+// pair across all reports, ancestors included, even when trees overlap.
+func walkTreeEdges(reports []treeReport, node func(it *process.Item, truncated bool), edge func(parent, child *process.Item)) {
+	seen := make(map[int32]bool)
+	visit := func(it *process.Item, truncated bool) {
+		if !seen[it.Pid] {
+			seen[it.Pid] = true
+			node(it, truncated)
+		}
+	}
+	seenEdges := make(map[[2]int32]bool)
+	link := func(parent, child *process.Item) {
+		key := [2]int32{parent.Pid, child.Pid}
+		if !seenEdges[key] {
+			seenEdges[key] = true
+			edge(parent, child)
+		}
+	}
+	var walk func(n *process.TreeNode)
+	walk = func(n *process.TreeNode) {
+		visit(n.Process, n.Truncated)
+		for _, c := range n.Children {
+			walk(c)
+			link(n.Process, c.Process)
+		}
+	}
+	for _, r := range reports {
+		for i, it := range r.Ancestors {
+			visit(it, false)
+			if i > 0 {
+				link(r.Ancestors[i-1], it)
+			}
+		}
+		walk(r.Tree)
+		if n := len(r.Ancestors); n > 0 {
+			link(r.Ancestors[n-1], r.Tree.Process)
+		}
+	}
+}
+
+func writeTreesDOT(w io.Writer, reports []treeReport) error {
+	var b strings.Builder
+	b.WriteString("digraph gokill {\n")
+	b.WriteString("  node [shape=box];\n")
+	walkTreeEdges(reports,
+		func(it *process.Item, truncated bool) {
+			label := treeLabel(it)
+			if truncated {
+				label += " …"
+			}
+			fmt.Fprintf(&b, "  p%d [label=%q];\n", it.Pid, label)
+		},
+		func(parent, child *process.Item) {
+			fmt.Fprintf(&b, "  p%d -> p%d;\n", parent.Pid, child.Pid)
+		})
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTreesMermaid(w io.Writer, reports []treeReport) error {
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	walkTreeEdges(reports,
+		func(it *process.Item, truncated bool) {
+			label := treeLabel(it)
+			if truncated {
+				label += " …"
+			}
+			fmt.Fprintf(&b, "  p%d[\"%s\"]\n", it.Pid, strings.ReplaceAll(label, `"`, "#quot;"))
+		},
+		func(parent, child *process.Item) {
+			fmt.Fprintf(&b, "  p%d --> p%d\n", parent.Pid, child.Pid)
+		})
+	_, err := io.WriteString(w, b.String())
+	return err
+}
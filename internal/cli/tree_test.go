@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+func treeItems() []*process.Item {
+	items := []*process.Item{
+		process.NewItem(1, "init", "root"),
+		process.NewItem(20, "bash", "alice"),
+		process.NewItem(31, "node", "alice", 3000, 3001),
+		process.NewItem(30, "make", "alice"),
+		process.NewItem(40, "sleep", "alice"),
+	}
+	parents := map[int32]int32{20: 1, 31: 20, 30: 20, 40: 30}
+	for _, it := range items {
+		it.PPid = parents[it.Pid]
+	}
+	return items
+}
+
+func TestWriteTreesTextMatchesDependencyView(t *testing.T) {
+	items := treeItems()
+	reports := buildTreeReports(items, []*process.Item{items[1]}, process.TreeOptions{}, true)
+
+	var buf bytes.Buffer
+	if err := writeTrees(&buf, reports, formatText); err != nil {
+		t.Fatalf("writeTrees: %v", err)
+	}
+	want := strings.Join([]string{
+		"Ancestors",
+		"└─ init (1)",
+		"",
+		"bash (20)",
+		"├─ make (30)",
+		"│  └─ sleep (40)",
+		"└─ node (31) [3000,3001]",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Fatalf("unexpected tree:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteTreesTextMarksTruncatedNodes(t *testing.T) {
+	items := treeItems()
+	reports := buildTreeReports(items, []*process.Item{items[0]}, process.TreeOptions{MaxDepth: 1}, false)
+
+	var buf bytes.Buffer
+	if err := writeTrees(&buf, reports, formatText); err != nil {
+		t.Fatalf("writeTrees: %v", err)
+	}
+	if !strings.Contains(buf.String(), "└─ bash (20)\n   └─ … (deeper)\n") {
+		t.Fatalf("expected a deeper marker under bash:\n%s", buf.String())
+	}
+}
+
+func TestWriteTreesGraphFormats(t *testing.T) {
+	items := treeItems()
+	reports := buildTreeReports(items, []*process.Item{items[2]}, process.TreeOptions{}, true)
+
+	var dot bytes.Buffer
+	if err := writeTrees(&dot, reports, formatDOT); err != nil {
+		t.Fatalf("writeTrees dot: %v", err)
+	}
+	for _, want := range []string{"digraph gokill {", `p31 [label="node (31) [3000,3001]"];`, "p1 -> p20;", "p20 -> p31;"} {
+		if !strings.Contains(dot.String(), want) {
+			t.Fatalf("expected %q in DOT output:\n%s", want, dot.String())
+		}
+	}
+
+	var mermaid bytes.Buffer
+	if err := writeTrees(&mermaid, reports, formatMermaid); err != nil {
+		t.Fatalf("writeTrees mermaid: %v", err)
+	}
+	for _, want := range []string{"graph TD", `p20["bash (20)"]`, "p1 --> p20", "p20 --> p31"} {
+		if !strings.Contains(mermaid.String(), want) {
+			t.Fatalf("expected %q in Mermaid output:\n%s", want, mermaid.String())
+		}
+	}
+}
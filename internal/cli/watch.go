@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/why"
+)
+
+// watchEvent is one NDJSON line printed by `gokill watch`.
+type watchEvent struct {
+	Time    time.Time          `json:"time"`
+	Event   process.ChangeKind `json:"event"`
+	Process *process.Item      `json:"process"`
+	Ports   []uint32           `json:"ports,omitempty"`
+	Why     *why.Source        `json:"why,omitempty"`
+	WhyErr  string             `json:"whyError,omitempty"`
+}
+
+// watchWhyConcurrency bounds how many --why analyses run at once, so a burst
+// of starts costs a few analysis timeouts instead of one per process.
+const watchWhyConcurrency = 8
+
+// snapshotFunc returns the processes to compare on each tick.
+type snapshotFunc func() ([]*process.Item, []error, error)
+
+func runWatch(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("watch", "[flags]", stderr)
+	interval := fs.Duration("interval", time.Second, "time between snapshots")
+	duration := fs.Duration("duration", 0, "stop after this long (0 = until interrupted)")
+	var names, users listFlag
+	fs.Var(&names, "name", "only watch processes with this exact executable or container name (repeatable)")
+	fs.Var(&users, "user", "only watch processes owned by this user (repeatable)")
+	withWhy := fs.Bool("why", false, "attach the why source to start events")
+	whyTimeout := fs.Duration("why-timeout", 500*time.Millisecond, "analysis timeout per started process with --why (up to 8 run in parallel)")
+	quiet := fs.Bool("quiet", false, "do not print collection warnings to stderr")
+	scan := addScanFlags(fs)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return parseExitCode(err)
+	}
+	if err := scan.apply(); err != nil {
+		fmt.Fprintf(stderr, "gokill watch: %v\n", err)
+		return exitUsage
+	}
+	if len(positional) > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if *interval <= 0 || *duration < 0 {
+		fmt.Fprintln(stderr, "gokill watch: --interval must be positive and --duration must not be negative")
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	sel := killSelector{names: names, users: users}
+	self := int32(os.Getpid())
+	snapshot := func() ([]*process.Item, []error, error) {
+		items, warnings, err := process.GetProcesses()
+		if err != nil {
+			return nil, warnings, err
+		}
+		matched, err := sel.match(items, self)
+		return matched, warnings, err
+	}
+
+	var annotate func(*watchEvent)
+	if *withWhy {
+		annotate = func(ev *watchEvent) {
+			// Only the source is reported, so the environment is not read.
+			result, err := why.AnalyzeWithTimeoutOptions(int(ev.Process.Pid), *whyTimeout, why.AnalyzeOptions{})
+			if result != nil {
+				ev.Why = &result.Source
+			}
+			if err != nil {
+				ev.WhyErr = err.Error()
+			}
+		}
+	}
+
+	var warnOut io.Writer = stderr
+	if *quiet {
+		warnOut = io.Discard
+	}
+	if err := watchLoop(ctx, stdout, warnOut, *interval, snapshot, annotate); err != nil {
+		fmt.Fprintf(stderr, "gokill watch: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// watchLoop takes a baseline snapshot and then prints one event per change
+// found on every tick until ctx is done. Only the baseline's collection
+// warnings are reported, so a long watch does not repeat them every second.
+func watchLoop(ctx context.Context, w, warnOut io.Writer, interval time.Duration, snapshot snapshotFunc, annotate func(*watchEvent)) error {
+	prev, warnings, err := snapshot()
+	if err != nil {
+		return err
+	}
+	printWarnings(warnOut, warnings)
+
+	enc := json.NewEncoder(w)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		next, _, err := snapshot()
+		if err != nil {
+			// A failed scan is transient; compare against the next one instead.
+			fmt.Fprintf(warnOut, "gokill: warning: %v\n", err)
+			continue
+		}
+		now := time.Now()
+		changes := process.Diff(prev, next)
+		events := make([]watchEvent, len(changes))
+		for i, c := range changes {
+			events[i] = watchEvent{Time: now, Event: c.Kind, Process: c.Item, Ports: c.Ports}
+		}
+		annotateStarts(events, annotate)
+		for _, ev := range events {
+			if err := enc.Encode(ev); err != nil {
+				return err
+			}
+		}
+		prev = next
+	}
+}
+
+// annotateStarts runs annotate on every start event, at most
+// watchWhyConcurrency at a time, and returns once all of them are done.
+func annotateStarts(events []watchEvent, annotate func(*watchEvent)) {
+	if annotate == nil {
+		return
+	}
+	sem := make(chan struct{}, watchWhyConcurrency)
+	var wg sync.WaitGroup
+	for i := range events {
+		if events[i].Event != process.ChangeStart {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(ev *watchEvent) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			annotate(ev)
+		}(&events[i])
+	}
+	wg.Wait()
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/why"
+)
+
+func TestWatchLoopEmitsChangesAsNDJSON(t *testing.T) {
+	web := process.NewItem(10, "web", "alice")
+	webListening := process.NewItem(10, "web", "alice", 8080)
+	job := process.NewItem(11, "job", "alice")
+	job.StartTime = time.UnixMilli(5000)
+
+	snapshots := [][]*process.Item{
+		{web},
+		{webListening, job},
+		{webListening},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	snapshot := func() ([]*process.Item, []error, error) {
+		s := snapshots[calls]
+		calls++
+		if calls == len(snapshots) {
+			cancel()
+		}
+		return s, nil, nil
+	}
+	annotate := func(ev *watchEvent) {
+		ev.Why = &why.Source{Type: why.SourceShell, Name: "bash"}
+	}
+
+	var out bytes.Buffer
+	if err := watchLoop(ctx, &out, io.Discard, time.Millisecond, snapshot, annotate); err != nil {
+		t.Fatalf("watchLoop: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []process.ChangeKind{process.ChangeStart, process.ChangePortOpen, process.ChangeExit}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d events, got %d:\n%s", len(want), len(lines), out.String())
+	}
+	for i, line := range lines {
+		var ev watchEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if ev.Event != want[i] {
+			t.Fatalf("event %d = %s, want %s", i, ev.Event, want[i])
+		}
+		if ev.Event == process.ChangeStart && (ev.Why == nil || ev.Why.Name != "bash") {
+			t.Fatalf("expected why source on start event: %s", line)
+		}
+		if ev.Event != process.ChangeStart && ev.Why != nil {
+			t.Fatalf("only start events should carry why: %s", line)
+		}
+	}
+	if !strings.Contains(lines[1], `"ports":[8080]`) {
+		t.Fatalf("expected opened port in event: %s", lines[1])
+	}
+}
+
+func TestAnnotateStartsRunsInParallelAndKeepsOrder(t *testing.T) {
+	events := make([]watchEvent, 20)
+	for i := range events {
+		events[i] = watchEvent{Event: process.ChangeStart, Process: process.NewItem(100+i, "job", "ci")}
+	}
+	events[3].Event = process.ChangeExit
+
+	var running, peak atomic.Int32
+	annotate := func(ev *watchEvent) {
+		n := running.Add(1)
+		for {
+			if p := peak.Load(); n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		ev.WhyErr = "analysed"
+		running.Add(-1)
+	}
+	annotateStarts(events, annotate)
+
+	if p := peak.Load(); p < 2 || p > watchWhyConcurrency {
+		t.Fatalf("expected between 2 and %d analyses at once, got %d", watchWhyConcurrency, p)
+	}
+	for i, ev := range events {
+		if ev.Process.Pid != int32(100+i) || (ev.WhyErr == "analysed") != (i != 3) {
+			t.Fatalf("event %d: pid %d, whyError %q", i, ev.Process.Pid, ev.WhyErr)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/why"
+)
+
+const formatText = "text"
+
+// whyReport is the JSON record printed by `gokill why --format json`.
+type whyReport struct {
+	Process *process.Item       `json:"process"`
+	Why     *why.AnalysisResult `json:"why"`
+	Error   string              `json:"error,omitempty"`
+}
+
+func runWhy(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("why", "[flags] <pid|:port|name>", stderr)
+	format := fs.String("format", formatText, "output format: text or json")
+	timeout := fs.Duration("timeout", 2*time.Second, "analysis timeout per process")
+	scan := addScanFlags(fs)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return parseExitCode(err)
+	}
+	if err := scan.apply(); err != nil {
+		fmt.Fprintf(stderr, "gokill why: %v\n", err)
+		return exitUsage
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	if *format != formatText && *format != formatJSON {
+		fmt.Fprintf(stderr, "gokill why: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	items, err := resolveTarget(positional[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "gokill why: %v\n", err)
+		return exitError
+	}
+	if len(items) == 0 {
+		fmt.Fprintf(stderr, "gokill why: no process matches %q\n", positional[0])
+		return exitNoMatch
+	}
+
+	reports := make([]whyReport, 0, len(items))
+	for _, it := range items {
+		result, err := why.AnalyzeWithTimeoutOptions(int(it.Pid), *timeout, why.AnalyzeOptions{EnvWarnings: true})
+		report := whyReport{Process: it, Why: result}
+		if err != nil {
+			report.Error = err.Error()
+		}
+		reports = append(reports, report)
+	}
+
+	if *format == formatJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			fmt.Fprintf(stderr, "gokill why: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	if err := writeWhyText(stdout, reports); err != nil {
+		fmt.Fprintf(stderr, "gokill why: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// writeWhyText prints each report using the details view layout, aligning the
+// "Label:\tvalue" lines into columns.
+func writeWhyText(w io.Writer, reports []whyReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprint(tw, process.FormatWhyReport(r.Process.Executable, int(r.Process.Pid), r.Process.Ports, r.Process.PublicListener, r.Why))
+		if r.Error != "" {
+			fmt.Fprintf(tw, "  (partial: %s)\n", r.Error)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/why"
+)
+
+func TestFilterByPortAndName(t *testing.T) {
+	items := []*process.Item{
+		process.NewItem(1, "nginx", "root", 80, 443),
+		process.NewItem(2, "NGINX", "www"),
+		process.NewItem(3, "node", "alice", 3000),
+	}
+	items[2].ContainerName = "api"
+
+	if got := filterByPort(items, 443); len(got) != 1 || got[0].Pid != 1 {
+		t.Fatalf("filterByPort(443) = %#v", got)
+	}
+	if got := filterByName(items, "nginx"); len(got) != 2 {
+		t.Fatalf("expected case-insensitive name match on 2 items, got %d", len(got))
+	}
+	if got := filterByName(items, "api"); len(got) != 1 || got[0].Pid != 3 {
+		t.Fatalf("expected container name match, got %#v", got)
+	}
+}
+
+func TestParsePort(t *testing.T) {
+	if p, err := parsePort("8080"); err != nil || p != 8080 {
+		t.Fatalf("parsePort(8080) = %d, %v", p, err)
+	}
+	for _, bad := range []string{"", "0", "70000", "http"} {
+		if _, err := parsePort(bad); err == nil {
+			t.Fatalf("parsePort(%q) should fail", bad)
+		}
+	}
+}
+
+func TestWriteWhyTextUsesDetailsLayout(t *testing.T) {
+	reports := []whyReport{{
+		Process: process.NewItem(42, "node", "alice", 3000),
+		Why: &why.AnalysisResult{
+			Ancestry: []why.ProcessInfo{
+				{PID: 1, Command: "systemd"},
+				{PID: 42, PPID: 1, Command: "node"},
+			},
+			Source:       why.Source{Type: why.SourceSystemd},
+			SystemdUnit:  "api.service",
+			RestartCount: 2,
+			Warnings:     []string{"Process is running as root"},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeWhyText(&buf, reports); err != nil {
+		t.Fatalf("writeWhyText: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"node (pid 42), port 3000",
+		"Why It Exists:",
+		"systemd (pid 1) → node (pid 42)",
+		"api.service",
+		"Restart Count: 2",
+		"⚠ Process is running as root",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\t") {
+		t.Fatalf("expected tabs to be expanded into aligned columns:\n%s", out)
+	}
+}
@@ -0,0 +1,53 @@
+package process
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatAge renders how long ago a process started in at most three
+// characters, e.g. "45s", "12m", "5h", "3d", "6w" or "2y".
+func FormatAge(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	const (
+		day  = 24 * time.Hour
+		week = 7 * day
+		year = 365 * day
+	)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < day:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	case d < week:
+		return fmt.Sprintf("%dd", int(d/day))
+	case d < year:
+		return fmt.Sprintf("%dw", int(d/week))
+	default:
+		return fmt.Sprintf("%dy", int(d/year))
+	}
+}
+
+// FormatStartTime renders a start time for list columns: the time of day
+// for processes started today (relative to now), the date otherwise. An
+// unknown (zero) start time renders as "n/a".
+func FormatStartTime(t, now time.Time) string {
+	if t.IsZero() {
+		return "n/a"
+	}
+	t = t.In(now.Location())
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := now.Date()
+	switch {
+	case y1 == y2 && m1 == m2 && d1 == d2:
+		return t.Format("15:04:05")
+	case y1 == y2:
+		return t.Format("Jan 02")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
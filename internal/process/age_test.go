@@ -0,0 +1,41 @@
+package process
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatAge(t *testing.T) {
+	cases := map[time.Duration]string{
+		-time.Second:         "0s",
+		45 * time.Second:     "45s",
+		5 * time.Minute:      "5m",
+		3 * time.Hour:        "3h",
+		3 * 24 * time.Hour:   "3d",
+		20 * 24 * time.Hour:  "2w",
+		800 * 24 * time.Hour: "2y",
+	}
+	for d, want := range cases {
+		if got := FormatAge(d); got != want {
+			t.Errorf("FormatAge(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestFormatStartTime(t *testing.T) {
+	now := time.Date(2024, 5, 20, 18, 0, 0, 0, time.UTC)
+	cases := []struct {
+		start time.Time
+		want  string
+	}{
+		{time.Date(2024, 5, 20, 9, 30, 5, 0, time.UTC), "09:30:05"},
+		{time.Date(2024, 4, 29, 9, 30, 5, 0, time.UTC), "Apr 29"},
+		{time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), "2023-12-01"},
+		{time.Time{}, "n/a"},
+	}
+	for _, c := range cases {
+		if got := FormatStartTime(c.start, now); got != c.want {
+			t.Errorf("FormatStartTime(%v) = %q, want %q", c.start, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,568 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+	"github.com/w31r4/gokill/internal/why"
+)
+
+// Details is everything the details view knows about one process. Values
+// that could not be read are left empty; the renderers substitute "n/a".
+// FormatDetails renders it as text, the TUI renders it from the same fields,
+// and it marshals to JSON as is.
+//
+// Context, Warnings, Verbose and Env are only filled in when the ancestry
+// analysis succeeded, matching what the text layout shows.
+type Details struct {
+	Pid            int32               `json:"pid"`
+	Name           string              `json:"name"`
+	User           string              `json:"user"`
+	Exe            string              `json:"exe,omitempty"`
+	Cmdline        string              `json:"cmdline,omitempty"`
+	StartTime      time.Time           `json:"startTime,omitzero"`
+	CPUPercent     float64             `json:"cpuPercent"`
+	MemPercent     float32             `json:"memPercent"`
+	Ports          []uint32            `json:"ports,omitempty"`
+	PublicListener bool                `json:"publicListener,omitempty"`
+	Why            *why.AnalysisResult `json:"why,omitempty"`
+	Context        *DetailsContext     `json:"context,omitempty"`
+	Warnings       []string            `json:"warnings,omitempty"`
+	Verbose        []DetailField       `json:"verbose,omitempty"`
+	Env            *DetailsEnv         `json:"env,omitempty"`
+}
+
+// DetailsContext holds the cheap resource counters shown in the Context
+// section. Zero means unavailable.
+type DetailsContext struct {
+	RSS     uint64 `json:"rss,omitempty"`
+	Threads int32  `json:"threads,omitempty"`
+	FDs     int32  `json:"fds,omitempty"`
+}
+
+// DetailsEnv is the process environment as shown in the Env section. Vars
+// are sorted and already redacted unless DetailsOptions.RevealEnvSecrets was
+// set; Error explains why the environment is missing or incomplete.
+type DetailsEnv struct {
+	Vars  []string `json:"vars,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// DetailField is one labelled value of the details view, e.g. "PID" and "42".
+type DetailField struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// maxEnvLines bounds the Env section so a huge environment does not drown the view.
+const maxEnvLines = 30
+
+// GetProcessDetails returns detailed information about a process.
+func GetProcessDetails(pid int) (string, error) {
+	return GetProcessDetailsWithOptions(pid, DetailsOptions{ShowEnv: runtime.GOOS == "linux"})
+}
+
+// GetProcessDetailsWithOptions returns the details of pid rendered by FormatDetails.
+func GetProcessDetailsWithOptions(pid int, opts DetailsOptions) (string, error) {
+	d, err := GetDetails(pid, opts)
+	if err != nil {
+		return "", err
+	}
+	return FormatDetails(d), nil
+}
+
+// GetDetails collects the details of pid. Only a missing process is an
+// error; everything else is best-effort.
+func GetDetails(pid int, opts DetailsOptions) (*Details, error) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return nil, fmt.Errorf("process with pid %d not found: %w", pid, err)
+	}
+
+	d := &Details{
+		Pid:        p.Pid,
+		Name:       fetchName(p),
+		User:       fetchUsername(p),
+		Exe:        fetchExe(p),
+		Cmdline:    fetchCmdline(p),
+		StartTime:  fetchStartTime(p),
+		CPUPercent: sampleCPUPercent(p),
+		MemPercent: fetchMemoryPercent(p),
+	}
+	d.Ports, d.PublicListener = scanProcessPorts(p)
+
+	// Analyze process ancestry and source with a 2 second timeout.
+	result, _ := why.AnalyzeWithTimeoutOptions(pid, 2*time.Second, why.AnalyzeOptions{
+		CollectEnv:  opts.ShowEnv,
+		EnvWarnings: true,
+	})
+	if result == nil {
+		return d, nil
+	}
+
+	d.Env = collectEnv(result, opts)
+	// The raw environment lives in Env, redacted; the analysis result may be
+	// shared with the analyzer cache, so it is copied rather than cleared.
+	analysis := *result
+	analysis.Env, analysis.EnvError = nil, ""
+	d.Why = &analysis
+	d.Context = collectContext(p)
+	d.Warnings = detailWarnings(result.Warnings, d.PublicListener)
+	d.Verbose = collectVerbose(p, opts)
+	return d, nil
+}
+
+func fetchUsername(p *process.Process) string {
+	user, err := p.Username()
+	if err != nil {
+		return ""
+	}
+	return user
+}
+
+// sampleCPUPercent 读取与列表共享的 CPU 采样器但不写入：返回自上一次列表扫描以来的使用率，
+// 首次见到的进程返回其生命周期平均值，因此不需要阻塞等待第二次采样，也不会缩短下一次扫描的采样区间。
+func sampleCPUPercent(p *process.Process) float64 {
+	createTime, err := p.CreateTime()
+	if err != nil {
+		return 0.0
+	}
+	times, err := p.Times()
+	if err != nil {
+		return 0.0
+	}
+	return usageSampler.peek(Key{Pid: p.Pid, CreateTime: createTime}, times.User+times.System, time.Now())
+}
+
+func fetchMemoryPercent(p *process.Process) float32 {
+	memPercent, err := p.MemoryPercent()
+	if err != nil {
+		return 0.0
+	}
+	return memPercent
+}
+
+func fetchStartTime(p *process.Process) time.Time {
+	createTime, err := p.CreateTime() // returns millis since epoch
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(createTime)
+}
+
+func fetchName(p *process.Process) string {
+	name, err := p.Name()
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+func fetchExe(p *process.Process) string {
+	exe, err := p.Exe()
+	if err != nil {
+		return ""
+	}
+	return exe
+}
+
+func fetchCmdline(p *process.Process) string {
+	cmdline, err := p.Cmdline()
+	if err != nil {
+		return ""
+	}
+	return cmdline
+}
+
+func scanProcessPorts(p *process.Process) ([]uint32, bool) {
+	if !shouldScanPorts() {
+		return nil, false
+	}
+	return newListenerLookup()(p)
+}
+
+func collectContext(p *process.Process) *DetailsContext {
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	var c DetailsContext
+	if memInfo, err := p.MemoryInfoWithContext(ctx); err == nil && memInfo != nil {
+		c.RSS = memInfo.RSS
+	}
+	if threads, err := p.NumThreadsWithContext(ctx); err == nil && threads > 0 {
+		c.Threads = threads
+	}
+	if fds, err := p.NumFDsWithContext(ctx); err == nil && fds > 0 {
+		c.FDs = fds
+	}
+	return &c
+}
+
+func collectEnv(result *why.AnalysisResult, opts DetailsOptions) *DetailsEnv {
+	if result == nil || !opts.ShowEnv {
+		return nil
+	}
+	reason := sanitizeEnvError(result.EnvError)
+	if len(result.Env) == 0 && reason == "" {
+		return nil
+	}
+
+	// Keep the output stable.
+	vars := make([]string, 0, len(result.Env))
+	for _, entry := range result.Env {
+		vars = append(vars, formatEnvEntry(entry, opts.RevealEnvSecrets))
+	}
+	sort.Strings(vars)
+	return &DetailsEnv{Vars: vars, Error: reason}
+}
+
+func detailWarnings(analysis []string, hasPublicListener bool) []string {
+	warnings := append([]string(nil), analysis...)
+	if hasPublicListener {
+		warnings = append(warnings, "Process is listening on a public interface (0.0.0.0/::)")
+	}
+	return dedupeStringsPreserveOrder(warnings)
+}
+
+// BaseFields returns the summary lines shown at the top of the details view.
+func (d *Details) BaseFields() []DetailField {
+	start := "n/a"
+	if !d.StartTime.IsZero() {
+		start = d.StartTime.Format("Jan 02 15:04")
+	}
+	exe := d.Exe
+	if exe == "" {
+		exe = "(permission denied or n/a)"
+	}
+	cmdline := d.Cmdline
+	if cmdline == "" {
+		cmdline = "(n/a)"
+	}
+
+	fields := []DetailField{
+		{"User", orNA(d.User)},
+		{"Name", orNA(d.Name)},
+		{"%CPU", fmt.Sprintf("%.1f", d.CPUPercent)},
+		{"%MEM", fmt.Sprintf("%.1f", d.MemPercent)},
+		{"Start", start},
+	}
+	if len(d.Ports) > 0 {
+		fields = append(fields, DetailField{"Ports", formatPorts(d.Ports)})
+	}
+	return append(fields,
+		DetailField{"Target", formatTargetSummary(d.Name, int(d.Pid), d.Ports)},
+		DetailField{"PID", fmt.Sprintf("%d", d.Pid)},
+		DetailField{"Exe", exe},
+		DetailField{"Command", cmdline},
+	)
+}
+
+// WhyFields returns the source, service and repository lines that follow the
+// ancestry chain. It is empty when the analysis failed.
+func (d *Details) WhyFields() []DetailField {
+	return whyFields(d.Why)
+}
+
+// ContextFields returns the lines of the Context section.
+func (d *Details) ContextFields() []DetailField {
+	var fields []DetailField
+	if state := formatSocketState(d.Ports, d.PublicListener); state != "" {
+		fields = append(fields, DetailField{"Socket State", state})
+	}
+	if d.Context == nil {
+		return fields
+	}
+	if resource := formatResourceContext(d.Context); resource != "" {
+		fields = append(fields, DetailField{"Resource", resource})
+	}
+	if d.Context.FDs > 0 {
+		fields = append(fields, DetailField{"Files", fmt.Sprintf("FDs %d", d.Context.FDs)})
+	}
+	return fields
+}
+
+// Lines returns the Env section as display lines: at most maxEnvLines
+// variables, escaped for line-based output, preceded by a note when the
+// environment is incomplete.
+func (e *DetailsEnv) Lines() []string {
+	return e.lines(maxEnvLines)
+}
+
+// AllLines is like Lines without the cap, for views that can scroll.
+func (e *DetailsEnv) AllLines() []string {
+	return e.lines(0)
+}
+
+func (e *DetailsEnv) lines(max int) []string {
+	if e == nil {
+		return nil
+	}
+	if len(e.Vars) == 0 {
+		if e.Error == "" {
+			return []string{"(empty)"}
+		}
+		return []string{fmt.Sprintf("(unavailable: %s)", e.Error)}
+	}
+
+	var lines []string
+	if e.Error != "" {
+		lines = append(lines, fmt.Sprintf("(partial: %s)", e.Error))
+	}
+	limit := len(e.Vars)
+	if max > 0 && limit > max {
+		limit = max
+	}
+	for _, v := range e.Vars[:limit] {
+		lines = append(lines, sanitizeEnvEntry(v))
+	}
+	if len(e.Vars) > limit {
+		lines = append(lines, fmt.Sprintf("… (%d more)", len(e.Vars)-limit))
+	}
+	return lines
+}
+
+// FormatDetails renders d as indented "Label:\tvalue" lines, with the ancestry
+// analysis in a "Why It Exists" block.
+func FormatDetails(d *Details) string {
+	var b strings.Builder
+	writeFields(&b, d.BaseFields())
+	if d.Why == nil {
+		return b.String()
+	}
+
+	writeWhyHeader(&b)
+	writeWhyBody(&b, d.Why)
+	appendContextSection(&b, d.ContextFields())
+	appendWarningsSection(&b, d.Warnings)
+	appendVerboseSection(&b, d.Verbose)
+	appendEnvSection(&b, d.Env)
+	writeWhyFooter(&b)
+	return b.String()
+}
+
+// FormatWhyReport renders an analysis result with the labels and layout of the
+// details view's "Why It Exists" block, preceded by a Target summary line.
+// It is meant for plain-text consumers such as `gokill why`.
+func FormatWhyReport(name string, pid int, ports []uint32, hasPublicListener bool, result *why.AnalysisResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  Target:\t%s\n", formatTargetSummary(name, pid, ports))
+	if result == nil {
+		return b.String()
+	}
+	writeWhyHeader(&b)
+	writeWhyBody(&b, result)
+	appendWarningsSection(&b, detailWarnings(result.Warnings, hasPublicListener))
+	writeWhyFooter(&b)
+	return b.String()
+}
+
+func writeFields(b *strings.Builder, fields []DetailField) {
+	for _, f := range fields {
+		fmt.Fprintf(b, "  %s:\t%s\n", f.Label, f.Value)
+	}
+}
+
+func writeWhyBody(b *strings.Builder, result *why.AnalysisResult) {
+	if len(result.Ancestry) > 0 {
+		fmt.Fprintf(b, "    %s\n", why.FormatAncestryChain(result.Ancestry))
+	}
+	fmt.Fprintf(b, "\n")
+	writeFields(b, whyFields(result))
+}
+
+func writeWhyHeader(b *strings.Builder) {
+	fmt.Fprintf(b, "\n  ─────────────────────────────────────\n")
+	fmt.Fprintf(b, "  Why It Exists:\n")
+}
+
+func writeWhyFooter(b *strings.Builder) {
+	fmt.Fprintf(b, "  ─────────────────────────────────────\n")
+}
+
+func whyFields(result *why.AnalysisResult) []DetailField {
+	if result == nil {
+		return nil
+	}
+	fields := []DetailField{{"Source", formatSourceLine(result.Source)}}
+	if result.SystemdUnit != "" {
+		fields = append(fields, DetailField{"Service", result.SystemdUnit})
+	} else if needsServiceLine(result.Source) {
+		fields = append(fields, DetailField{"Service", result.Source.Name})
+	}
+	if result.ContainerID != "" {
+		fields = append(fields, DetailField{"Container", result.ContainerID})
+	}
+	if result.WorkingDir != "" {
+		fields = append(fields, DetailField{"Working Dir", result.WorkingDir})
+	}
+	if result.GitRepo != "" {
+		repo := result.GitRepo
+		if result.GitBranch != "" {
+			repo = fmt.Sprintf("%s (%s)", result.GitRepo, result.GitBranch)
+		}
+		fields = append(fields, DetailField{"Git Repo", repo})
+	}
+	return append(fields, DetailField{"Restart Count", fmt.Sprintf("%d", result.RestartCount)})
+}
+
+func formatSourceLine(source why.Source) string {
+	sourceStr := string(source.Type)
+	if sourceStr == "" {
+		sourceStr = string(why.SourceUnknown)
+	}
+	sourceName := source.Name
+	if source.Type == why.SourceSystemd || source.Type == why.SourceLaunchd || source.Type == why.SourceDocker {
+		sourceName = ""
+	}
+	if sourceName != "" && sourceName != sourceStr {
+		return fmt.Sprintf("%s (%s)", sourceName, sourceStr)
+	}
+	return sourceStr
+}
+
+func needsServiceLine(source why.Source) bool {
+	if source.Name == "" {
+		return false
+	}
+	return source.Type == why.SourceSystemd || source.Type == why.SourceLaunchd
+}
+
+func appendContextSection(b *strings.Builder, fields []DetailField) {
+	if len(fields) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n  Context:\n")
+	writeFields(b, fields)
+}
+
+func appendWarningsSection(b *strings.Builder, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n  Warnings:\n")
+	for _, warning := range warnings {
+		fmt.Fprintf(b, "  ⚠ %s\n", warning)
+	}
+}
+
+func appendVerboseSection(b *strings.Builder, fields []DetailField) {
+	if fields == nil {
+		return
+	}
+	fmt.Fprintf(b, "\n  Verbose:\n")
+	if len(fields) == 0 {
+		fmt.Fprintf(b, "  (unavailable)\n")
+		return
+	}
+	writeFields(b, fields)
+}
+
+func appendEnvSection(b *strings.Builder, env *DetailsEnv) {
+	if env == nil {
+		return
+	}
+	fmt.Fprintf(b, "\n  Env:\n")
+	for _, line := range env.Lines() {
+		fmt.Fprintf(b, "  %s\n", line)
+	}
+}
+
+func dedupeStringsPreserveOrder(in []string) []string {
+	if len(in) < 2 {
+		return in
+	}
+	seen := make(map[string]struct{}, len(in))
+	out := in[:0]
+	for _, s := range in {
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		out = append(out, s)
+	}
+	return out
+}
+
+func formatEnvEntry(entry string, revealSecrets bool) string {
+	key, value, ok := strings.Cut(entry, "=")
+	if !ok {
+		return entry
+	}
+	if !revealSecrets && shouldRedactEnvKey(key) {
+		value = "<redacted>"
+	}
+	return key + "=" + value
+}
+
+func sanitizeEnvEntry(s string) string {
+	// Make env display safe for our line-based formatter.
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	s = strings.ReplaceAll(s, "\r", "\\r")
+	s = strings.ReplaceAll(s, "\t", "\\t")
+	s = strings.ReplaceAll(s, "\x1b", "") // strip ANSI ESC
+	s = truncateRunes(s, 220)
+	return s
+}
+
+func sanitizeEnvError(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "\r", " ")
+	s = strings.ReplaceAll(s, "\t", " ")
+	s = strings.ReplaceAll(s, "\x1b", "")
+	s = strings.Join(strings.Fields(s), " ")
+	return truncateRunes(s, 120)
+}
+
+func orNA(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "n/a"
+	}
+	return s
+}
+
+func formatTargetSummary(name string, pid int, ports []uint32) string {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" || trimmed == "n/a" {
+		trimmed = "process"
+	}
+	summary := fmt.Sprintf("%s (pid %d)", trimmed, pid)
+	if len(ports) > 0 {
+		summary = fmt.Sprintf("%s, port %d", summary, ports[0])
+		if len(ports) > 1 {
+			summary = fmt.Sprintf("%s (+%d)", summary, len(ports)-1)
+		}
+	}
+	return summary
+}
+
+func formatSocketState(ports []uint32, hasPublicListener bool) string {
+	if len(ports) == 0 {
+		return ""
+	}
+	state := fmt.Sprintf("listening %d", len(ports))
+	if hasPublicListener {
+		state += " (public)"
+	}
+	return state
+}
+
+func formatResourceContext(c *DetailsContext) string {
+	var parts []string
+	if c.RSS > 0 {
+		rssMB := float64(c.RSS) / (1024 * 1024)
+		parts = append(parts, fmt.Sprintf("RSS %.1f MB", rssMB))
+	}
+	if c.Threads > 0 {
+		parts = append(parts, fmt.Sprintf("Threads %d", c.Threads))
+	}
+	return strings.Join(parts, " • ")
+}
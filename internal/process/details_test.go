@@ -0,0 +1,74 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/w31r4/gokill/internal/why"
+)
+
+func TestFormatDetailsLayout(t *testing.T) {
+	vars := []string{"PATH=/usr/bin:/bin"}
+	for i := 0; i < maxEnvLines+2; i++ {
+		vars = append(vars, fmt.Sprintf("V%02d=x", i))
+	}
+	d := &Details{
+		Pid:            42,
+		Name:           "web",
+		Ports:          []uint32{8080},
+		PublicListener: true,
+		Why: &why.AnalysisResult{
+			Ancestry: []why.ProcessInfo{{PID: 1, Command: "init"}, {PID: 42, Command: "web"}},
+			Source:   why.Source{Type: why.SourceShell, Name: "bash"},
+		},
+		Context:  &DetailsContext{Threads: 4},
+		Warnings: detailWarnings(nil, true),
+		Env:      &DetailsEnv{Vars: vars},
+	}
+
+	out := FormatDetails(d)
+	for _, want := range []string{
+		"  User:\tn/a\n",
+		"  Target:\tweb (pid 42), port 8080\n",
+		"  Exe:\t(permission denied or n/a)\n",
+		"    init (pid 1) → web (pid 42)\n",
+		"\n  Source:\tbash (shell)\n",
+		"  Socket State:\tlistening 1 (public)\n",
+		"  Resource:\tThreads 4\n",
+		"  ⚠ Process is listening on a public interface (0.0.0.0/::)\n",
+		"  PATH=/usr/bin:/bin\n",
+		"  … (3 more)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Verbose:") {
+		t.Fatalf("expected no Verbose section when it was not collected:\n%s", out)
+	}
+}
+
+func TestGetDetailsKeepsEnvOutOfAnalysis(t *testing.T) {
+	d, err := GetDetails(os.Getpid(), DetailsOptions{ShowEnv: true})
+	if err != nil {
+		t.Fatalf("GetDetails: %v", err)
+	}
+	if int(d.Pid) != os.Getpid() || d.StartTime.IsZero() {
+		t.Fatalf("unexpected details for self: %+v", d)
+	}
+	if d.Why != nil && (len(d.Why.Env) != 0 || d.Why.EnvError != "") {
+		t.Fatalf("expected the raw environment to stay out of Why, got %d vars", len(d.Why.Env))
+	}
+
+	env := collectEnv(&why.AnalysisResult{Env: []string{"API_TOKEN=hunter2"}}, DetailsOptions{ShowEnv: true})
+	raw, err := json.Marshal(&Details{Env: env})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(raw), "hunter2") {
+		t.Fatalf("expected secrets to be redacted in JSON, got %s", raw)
+	}
+}
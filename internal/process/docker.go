@@ -157,5 +157,5 @@ func (r *dockerNetworkResolver) getOrInspect(network string) []containerEntry {
 func StopContainer(name string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
-	return exec.CommandContext(ctx, "docker", "stop", name).Run()
+	return RunManagerCommand(ctx, DockerStopCommand(name))
 }
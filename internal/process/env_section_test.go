@@ -9,7 +9,7 @@ import (
 
 func TestAppendEnvSectionUnavailable(t *testing.T) {
 	var b strings.Builder
-	appendEnvSection(&b, &why.AnalysisResult{EnvError: "permission denied"}, DetailsOptions{ShowEnv: true})
+	appendEnvSection(&b, collectEnv(&why.AnalysisResult{EnvError: "permission denied"}, DetailsOptions{ShowEnv: true}))
 	out := b.String()
 	if !strings.Contains(out, "Env:") {
 		t.Fatalf("expected Env header, got: %q", out)
@@ -21,7 +21,7 @@ func TestAppendEnvSectionUnavailable(t *testing.T) {
 
 func TestAppendEnvSectionPartial(t *testing.T) {
 	var b strings.Builder
-	appendEnvSection(&b, &why.AnalysisResult{Env: []string{"A=1"}, EnvError: "truncated: maxBytes=65536"}, DetailsOptions{ShowEnv: true})
+	appendEnvSection(&b, collectEnv(&why.AnalysisResult{Env: []string{"A=1"}, EnvError: "truncated: maxBytes=65536"}, DetailsOptions{ShowEnv: true}))
 	out := b.String()
 	if !strings.Contains(out, "Env:") {
 		t.Fatalf("expected Env header, got: %q", out)
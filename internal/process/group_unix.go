@@ -0,0 +1,59 @@
+//go:build !windows
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// TargetGroup returns the process group of pid, provided the group can be
+// signalled as a whole. The groups of init and of gokill itself are refused:
+// signalling either would take down far more than the user asked for.
+func TargetGroup(pid int32) (int, error) {
+	pgid, err := syscall.Getpgid(int(pid))
+	if err != nil {
+		return 0, fmt.Errorf("process group of pid %d: %w", pid, err)
+	}
+	if err := checkGroup(pgid); err != nil {
+		return 0, err
+	}
+	return pgid, nil
+}
+
+func checkGroup(pgid int) error {
+	if pgid <= 1 {
+		return fmt.Errorf("refusing to signal process group %d", pgid)
+	}
+	if pgid == syscall.Getpgrp() {
+		return fmt.Errorf("refusing to signal process group %d: gokill itself belongs to it", pgid)
+	}
+	return nil
+}
+
+// SignalGroup sends sig to every process in group pgid with kill(-pgid).
+func SignalGroup(pgid int, sig syscall.Signal) error {
+	if err := checkGroup(pgid); err != nil {
+		return err
+	}
+	if err := syscall.Kill(-pgid, sig); err != nil {
+		if err == syscall.ESRCH {
+			return os.ErrProcessDone
+		}
+		return os.NewSyscallError("kill", err)
+	}
+	return nil
+}
+
+// GroupMembers returns the items that belong to process group pgid, in the
+// order of items. Items whose group cannot be read are skipped.
+func GroupMembers(items []*Item, pgid int) []*Item {
+	var out []*Item
+	for _, it := range items {
+		if g, err := syscall.Getpgid(int(it.Pid)); err == nil && g == pgid {
+			out = append(out, it)
+		}
+	}
+	return out
+}
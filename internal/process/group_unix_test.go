@@ -0,0 +1,57 @@
+//go:build !windows
+
+package process
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestTargetGroupRefusesOwnGroup(t *testing.T) {
+	if _, err := TargetGroup(int32(os.Getpid())); err == nil {
+		t.Fatal("expected gokill's own process group to be refused")
+	}
+}
+
+func TestTerminateGroupStopsEveryMember(t *testing.T) {
+	leader := exec.Command("sleep", "30")
+	leader.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := leader.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	member := exec.Command("sleep", "30")
+	member.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Pgid: leader.Process.Pid}
+	if err := member.Start(); err != nil {
+		leader.Process.Kill()
+		leader.Wait()
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	// Reap both right away so they do not linger as zombies.
+	go leader.Wait()
+	go member.Wait()
+
+	pgid, err := TargetGroup(int32(leader.Process.Pid))
+	if err != nil || pgid != leader.Process.Pid {
+		t.Fatalf("TargetGroup = %d, %v; want %d", pgid, err, leader.Process.Pid)
+	}
+	items := []*Item{
+		{Pid: int32(leader.Process.Pid), Executable: "sleep"},
+		{Pid: int32(member.Process.Pid), Executable: "sleep"},
+		{Pid: int32(os.Getpid()), Executable: "go test"},
+	}
+	members := GroupMembers(items, pgid)
+	if len(members) != 2 {
+		t.Fatalf("expected both sleeps in the group, got %d members", len(members))
+	}
+
+	res := TerminateGroup(context.Background(), pgid, members, TerminateOptions{Grace: 2 * time.Second, PollInterval: 10 * time.Millisecond})
+	for _, r := range res {
+		if r.Outcome != OutcomeExited {
+			t.Fatalf("pid %d: %s", r.Item.Pid, r.Summary())
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package process
+
+import (
+	"errors"
+	"syscall"
+)
+
+var errNoGroups = errors.New("process groups are not supported on windows")
+
+// TargetGroup is not supported on Windows.
+func TargetGroup(pid int32) (int, error) {
+	return 0, errNoGroups
+}
+
+// SignalGroup is not supported on Windows.
+func SignalGroup(pgid int, sig syscall.Signal) error {
+	return errNoGroups
+}
+
+// GroupMembers is not supported on Windows and returns nil.
+func GroupMembers(items []*Item, pgid int) []*Item {
+	return nil
+}
@@ -0,0 +1,166 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// inspectTimeout bounds each of the on-demand loaders below. They read
+// everything about one process, so they get more room than a list scan.
+const inspectTimeout = 3 * time.Second
+
+// Socket is one socket held by a process, listening or connected.
+type Socket struct {
+	FD     uint32 `json:"fd"`
+	Proto  string `json:"proto"` // tcp, tcp6, udp, udp6 or unix
+	Local  string `json:"local"`
+	Remote string `json:"remote,omitempty"`
+	State  string `json:"state,omitempty"`
+}
+
+// OpenFile is one open file descriptor and what it points at: a path, or a
+// kernel object such as "socket:[1234]" or "pipe:[5678]".
+type OpenFile struct {
+	FD     uint64 `json:"fd"`
+	Target string `json:"target"`
+}
+
+// Thread is one thread of a process. CPUTime is user + system seconds.
+// Name and State are empty where the platform does not report them.
+type Thread struct {
+	TID     int32   `json:"tid"`
+	Name    string  `json:"name,omitempty"`
+	State   string  `json:"state,omitempty"`
+	CPUTime float64 `json:"cpuTime"`
+}
+
+// Limit is one row of a process's resource limits, e.g. "Max open files".
+type Limit struct {
+	Name  string `json:"name"`
+	Soft  string `json:"soft"`
+	Hard  string `json:"hard"`
+	Units string `json:"units,omitempty"`
+}
+
+// Cgroup is one cgroup membership of a process. Hierarchy "0" with the
+// controllers of the unified (v2) hierarchy is the cgroup v2 entry.
+type Cgroup struct {
+	Hierarchy   string   `json:"hierarchy"`
+	Controllers []string `json:"controllers,omitempty"`
+	Path        string   `json:"path"`
+}
+
+// GetSockets returns every socket pid holds, sorted by protocol and file
+// descriptor. Unlike the list scan it includes connected and unix sockets.
+func GetSockets(pid int) ([]Socket, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), inspectTimeout)
+	defer cancel()
+
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return nil, fmt.Errorf("process with pid %d not found: %w", pid, err)
+	}
+	conns, err := p.ConnectionsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sockets := make([]Socket, 0, len(conns))
+	for _, c := range conns {
+		s := Socket{FD: c.Fd, Proto: socketProto(c.Family, c.Type), State: c.Status}
+		if s.Proto == "unix" {
+			s.Local = c.Laddr.IP
+			if s.Local == "" {
+				s.Local = "(unnamed)"
+			}
+		} else {
+			ip := c.Laddr.IP
+			if ip == "" {
+				ip = "*"
+			}
+			s.Local = formatIPPort(ip, c.Laddr.Port)
+			if c.Raddr.IP != "" || c.Raddr.Port != 0 {
+				s.Remote = formatIPPort(c.Raddr.IP, c.Raddr.Port)
+			}
+		}
+		if s.State == "NONE" {
+			s.State = ""
+		}
+		sockets = append(sockets, s)
+	}
+	sort.SliceStable(sockets, func(i, j int) bool {
+		if sockets[i].Proto != sockets[j].Proto {
+			return sockets[i].Proto < sockets[j].Proto
+		}
+		return sockets[i].FD < sockets[j].FD
+	})
+	return sockets, nil
+}
+
+func socketProto(family, typ uint32) string {
+	switch family {
+	case syscall.AF_UNIX:
+		return "unix"
+	case syscall.AF_INET6:
+		if typ == syscall.SOCK_DGRAM {
+			return "udp6"
+		}
+		return "tcp6"
+	}
+	if typ == syscall.SOCK_DGRAM {
+		return "udp"
+	}
+	return "tcp"
+}
+
+// GetOpenFiles returns every open file descriptor of pid, sorted by number.
+func GetOpenFiles(pid int) ([]OpenFile, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), inspectTimeout)
+	defer cancel()
+
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return nil, fmt.Errorf("process with pid %d not found: %w", pid, err)
+	}
+	stats, err := p.OpenFilesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]OpenFile, 0, len(stats))
+	for _, st := range stats {
+		files = append(files, OpenFile{FD: st.Fd, Target: st.Path})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].FD < files[j].FD })
+	return files, nil
+}
+
+// GetEnv reads the full environment of pid, sorted, with secret-looking
+// values redacted unless revealSecrets is set.
+func GetEnv(pid int, revealSecrets bool) (*DetailsEnv, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), inspectTimeout)
+	defer cancel()
+
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return nil, fmt.Errorf("process with pid %d not found: %w", pid, err)
+	}
+	environ, err := p.EnvironWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vars := make([]string, 0, len(environ))
+	for _, entry := range environ {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		vars = append(vars, formatEnvEntry(entry, revealSecrets))
+	}
+	sort.Strings(vars)
+	return &DetailsEnv{Vars: vars}, nil
+}
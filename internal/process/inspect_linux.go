@@ -0,0 +1,184 @@
+//go:build linux
+
+package process
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is where the cgroup filesystem is mounted.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// clockTicks is USER_HZ, the unit of the CPU times in /proc/<pid>/task/<tid>/stat.
+// It is 100 on every Linux architecture gokill supports.
+const clockTicks = 100
+
+// threadStates names the state letters of /proc/<pid>/stat.
+var threadStates = map[string]string{
+	"R": "running",
+	"S": "sleeping",
+	"D": "disk sleep",
+	"Z": "zombie",
+	"T": "stopped",
+	"t": "tracing stop",
+	"X": "dead",
+	"I": "idle",
+}
+
+// GetThreads returns every thread of pid with its name, state and CPU time,
+// sorted by TID.
+func GetThreads(pid int) ([]Thread, error) {
+	taskDir := filepath.Join(procRoot, strconv.Itoa(pid), "task")
+	entries, err := os.ReadDir(taskDir)
+	if err != nil {
+		return nil, fmt.Errorf("process with pid %d not found: %w", pid, err)
+	}
+	threads := make([]Thread, 0, len(entries))
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(taskDir, e.Name(), "stat"))
+		if err != nil {
+			continue // the thread exited while we were listing
+		}
+		if t, ok := parseTaskStat(string(data)); ok {
+			threads = append(threads, t)
+		}
+	}
+	sort.Slice(threads, func(i, j int) bool { return threads[i].TID < threads[j].TID })
+	return threads, nil
+}
+
+// parseTaskStat parses one /proc/<pid>/task/<tid>/stat line. The name is
+// enclosed in parentheses and may itself contain spaces or parentheses, so
+// the fields are split after the last ')'.
+func parseTaskStat(line string) (Thread, bool) {
+	open := strings.IndexByte(line, '(')
+	end := strings.LastIndexByte(line, ')')
+	if open < 0 || end < open {
+		return Thread{}, false
+	}
+	tid, err := strconv.ParseInt(strings.TrimSpace(line[:open]), 10, 32)
+	if err != nil {
+		return Thread{}, false
+	}
+	// After the name: state(3) ppid pgrp session tty_nr tpgid flags minflt
+	// cminflt majflt cmajflt utime(14) stime(15) ...
+	fields := strings.Fields(line[end+1:])
+	if len(fields) < 13 {
+		return Thread{}, false
+	}
+	utime, _ := strconv.ParseFloat(fields[11], 64)
+	stime, _ := strconv.ParseFloat(fields[12], 64)
+	state := fields[0]
+	if name, ok := threadStates[state]; ok {
+		state = name
+	}
+	return Thread{
+		TID:     int32(tid),
+		Name:    line[open+1 : end],
+		State:   state,
+		CPUTime: (utime + stime) / clockTicks,
+	}, true
+}
+
+// GetLimits returns the full resource limit table of pid.
+func GetLimits(pid int) ([]Limit, error) {
+	f, err := os.Open(filepath.Join(procRoot, strconv.Itoa(pid), "limits"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseLimits(f), nil
+}
+
+// parseLimits parses /proc/<pid>/limits. The table is fixed-width and limit
+// names contain spaces, so columns are cut at the offsets of the header.
+func parseLimits(r io.Reader) []Limit {
+	sc := bufio.NewScanner(r)
+	if !sc.Scan() {
+		return nil
+	}
+	header := sc.Text()
+	soft := strings.Index(header, "Soft Limit")
+	hard := strings.Index(header, "Hard Limit")
+	units := strings.Index(header, "Units")
+	if soft < 0 || hard < soft || units < hard {
+		return nil
+	}
+
+	column := func(line string, from, to int) string {
+		if from >= len(line) {
+			return ""
+		}
+		if to < 0 || to > len(line) {
+			to = len(line)
+		}
+		return strings.TrimSpace(line[from:to])
+	}
+
+	var limits []Limit
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		limits = append(limits, Limit{
+			Name:  column(line, 0, soft),
+			Soft:  column(line, soft, hard),
+			Hard:  column(line, hard, units),
+			Units: column(line, units, -1),
+		})
+	}
+	return limits
+}
+
+// GetCgroups returns the cgroup memberships of pid. For the unified (v2)
+// hierarchy the enabled controllers are read from the cgroup directory.
+func GetCgroups(pid int) ([]Cgroup, error) {
+	f, err := os.Open(filepath.Join(procRoot, strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cgroups := parseCgroups(f)
+	for i, cg := range cgroups {
+		if cg.Hierarchy != "0" || len(cg.Controllers) > 0 {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(cgroupRoot, cg.Path, "cgroup.controllers"))
+		if err == nil {
+			cgroups[i].Controllers = strings.Fields(string(data))
+		}
+	}
+	return cgroups, nil
+}
+
+// parseCgroups parses /proc/<pid>/cgroup lines of the form
+// "hierarchy-ID:controller-list:path".
+func parseCgroups(r io.Reader) []Cgroup {
+	var cgroups []Cgroup
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		id, rest, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		controllers, path, ok := strings.Cut(rest, ":")
+		if !ok {
+			continue
+		}
+		cg := Cgroup{Hierarchy: id, Path: path}
+		if controllers != "" {
+			cg.Controllers = strings.Split(controllers, ",")
+		}
+		cgroups = append(cgroups, cg)
+	}
+	return cgroups
+}
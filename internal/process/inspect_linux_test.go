@@ -0,0 +1,80 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const procLimits = `Limit                     Soft Limit           Hard Limit           Units     
+Max cpu time              unlimited            unlimited            seconds   
+Max open files            1024                 524288               files     
+Max realtime timeout      unlimited            unlimited            us        
+`
+
+func TestParseLimits(t *testing.T) {
+	limits := parseLimits(strings.NewReader(procLimits))
+	want := []Limit{
+		{"Max cpu time", "unlimited", "unlimited", "seconds"},
+		{"Max open files", "1024", "524288", "files"},
+		{"Max realtime timeout", "unlimited", "unlimited", "us"},
+	}
+	if len(limits) != len(want) {
+		t.Fatalf("got %d limits, want %d: %+v", len(limits), len(want), limits)
+	}
+	for i, w := range want {
+		if limits[i] != w {
+			t.Fatalf("limit %d = %+v, want %+v", i, limits[i], w)
+		}
+	}
+}
+
+func TestParseTaskStat(t *testing.T) {
+	line := "4242 (worker (io) 2) S 1 4242 4242 0 -1 4194560 100 0 0 0 250 50 0 0 20 0 1 0 100 0 0"
+	th, ok := parseTaskStat(line)
+	if !ok {
+		t.Fatalf("expected %q to parse", line)
+	}
+	if th.TID != 4242 || th.Name != "worker (io) 2" || th.State != "sleeping" || th.CPUTime != 3 {
+		t.Fatalf("unexpected thread: %+v", th)
+	}
+	if _, ok := parseTaskStat("garbage"); ok {
+		t.Fatalf("expected garbage not to parse")
+	}
+}
+
+func TestGetCgroupsReadsV2Controllers(t *testing.T) {
+	root := t.TempDir()
+	oldProc, oldCgroup := procRoot, cgroupRoot
+	procRoot, cgroupRoot = filepath.Join(root, "proc"), filepath.Join(root, "cgroup")
+	defer func() { procRoot, cgroupRoot = oldProc, oldCgroup }()
+
+	write := func(path, data string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(procRoot, "7", "cgroup"), "4:memory,cpu:/docker/abc\n0::/system.slice/web.service\n")
+	write(filepath.Join(cgroupRoot, "system.slice", "web.service", "cgroup.controllers"), "cpu io memory pids\n")
+
+	cgroups, err := GetCgroups(7)
+	if err != nil {
+		t.Fatalf("GetCgroups: %v", err)
+	}
+	if len(cgroups) != 2 {
+		t.Fatalf("got %d cgroups, want 2: %+v", len(cgroups), cgroups)
+	}
+	if v1 := cgroups[0]; v1.Hierarchy != "4" || strings.Join(v1.Controllers, ",") != "memory,cpu" || v1.Path != "/docker/abc" {
+		t.Fatalf("unexpected v1 entry: %+v", v1)
+	}
+	if v2 := cgroups[1]; v2.Path != "/system.slice/web.service" || strings.Join(v2.Controllers, " ") != "cpu io memory pids" {
+		t.Fatalf("unexpected v2 entry: %+v", v2)
+	}
+}
@@ -0,0 +1,44 @@
+//go:build !linux
+
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// GetThreads returns every thread of pid with its CPU time, sorted by TID.
+// Names and states are not available outside Linux.
+func GetThreads(pid int) ([]Thread, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), inspectTimeout)
+	defer cancel()
+
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return nil, fmt.Errorf("process with pid %d not found: %w", pid, err)
+	}
+	times, err := p.ThreadsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	threads := make([]Thread, 0, len(times))
+	for tid, t := range times {
+		threads = append(threads, Thread{TID: tid, CPUTime: t.User + t.System})
+	}
+	sort.Slice(threads, func(i, j int) bool { return threads[i].TID < threads[j].TID })
+	return threads, nil
+}
+
+// GetLimits is only implemented on Linux, where the table comes from procfs.
+func GetLimits(pid int) ([]Limit, error) {
+	return nil, errors.New("resource limits are only available on Linux")
+}
+
+// GetCgroups is only implemented on Linux.
+func GetCgroups(pid int) ([]Cgroup, error) {
+	return nil, errors.New("cgroups are only available on Linux")
+}
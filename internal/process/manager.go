@@ -0,0 +1,243 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+	"github.com/w31r4/gokill/internal/why"
+)
+
+// managerCommandTimeout bounds a stop command. systemctl waits for the
+// unit's own stop timeout, so this is generous.
+const managerCommandTimeout = 30 * time.Second
+
+// ManagerCommand is a command that asks the manager supervising a process to
+// stop it. Killing such a process directly just gets it restarted.
+type ManagerCommand struct {
+	// Manager is the tool the command talks to: "systemd", "pm2",
+	// "supervisord" or "docker".
+	Manager string
+	// Env holds extra environment entries, e.g. the PM2_HOME of the target.
+	Env []string
+	// Args is the command line; Args[0] is looked up in PATH.
+	Args []string
+}
+
+// String renders the command as it could be typed into a shell, including
+// its extra environment, e.g. "systemctl stop nginx.service".
+func (c ManagerCommand) String() string {
+	parts := make([]string, 0, len(c.Env)+len(c.Args))
+	for _, e := range c.Env {
+		parts = append(parts, shellQuote(e))
+	}
+	for _, a := range c.Args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote single-quotes s unless it consists of characters a shell leaves
+// alone.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%_+=:,./-", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// DockerStopCommand returns the command that stops container name.
+func DockerStopCommand(name string) ManagerCommand {
+	return ManagerCommand{Manager: "docker", Args: []string{"docker", "stop", name}}
+}
+
+// RunManagerCommand runs cmd and waits for it to finish. The binary is
+// looked up in PATH, so tests can substitute fake ones. A failure includes
+// the last line the command printed, which usually says why.
+func RunManagerCommand(ctx context.Context, cmd ManagerCommand) error {
+	if len(cmd.Args) == 0 {
+		return errors.New("empty manager command")
+	}
+	ctx, cancel := context.WithTimeout(ctx, managerCommandTimeout)
+	defer cancel()
+
+	c := exec.CommandContext(ctx, cmd.Args[0], cmd.Args[1:]...)
+	if len(cmd.Env) > 0 {
+		c.Env = append(os.Environ(), cmd.Env...)
+	}
+	out, err := c.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return fmt.Errorf("%s: %w: %s", cmd, err, last)
+	}
+	return fmt.Errorf("%s: %w", cmd, err)
+}
+
+// ManagerStopCommand works out how to stop it through the manager that
+// supervises it, from the source detected by the why package: systemd units
+// are stopped with systemctl (--user for units of a user manager), pm2 apps
+// with pm2 stop, supervisord programs with supervisorctl stop and containers
+// with docker stop. The pm2 id and supervisord program come from the
+// variables those managers put into the environment of their children. An
+// error explains why no command could be derived.
+func ManagerStopCommand(it *Item) (*ManagerCommand, error) {
+	r := managerResolver{
+		analyze: func(pid int) (*why.AnalysisResult, error) {
+			// The variables the managers set are read by environ, so the
+			// analysis itself does not need the environment.
+			return why.AnalyzeWithTimeoutOptions(pid, 2*time.Second, why.AnalyzeOptions{})
+		},
+		environ: readEnviron,
+		cgroups: GetCgroups,
+		uid:     os.Getuid(),
+	}
+	return r.resolve(it)
+}
+
+// managerResolver holds ManagerStopCommand's inputs so tests can replace them.
+type managerResolver struct {
+	analyze func(pid int) (*why.AnalysisResult, error)
+	environ func(pid int32) ([]string, error)
+	cgroups func(pid int) ([]Cgroup, error)
+	uid     int
+}
+
+func (r managerResolver) resolve(it *Item) (*ManagerCommand, error) {
+	if it.ContainerName != "" {
+		cmd := DockerStopCommand(it.ContainerName)
+		return &cmd, nil
+	}
+	res, err := r.analyze(int(it.Pid))
+	if res == nil {
+		if err == nil {
+			err = errors.New("no analysis result")
+		}
+		return nil, fmt.Errorf("cannot tell what manages %s (%d): %w", it.Executable, it.Pid, err)
+	}
+
+	switch res.Source.Type {
+	case why.SourceDocker:
+		id := res.ContainerID
+		if id == "" {
+			return nil, fmt.Errorf("%s (%d) runs in a container, but its ID is unknown", it.Executable, it.Pid)
+		}
+		cmd := DockerStopCommand(id)
+		return &cmd, nil
+	case why.SourceSystemd:
+		return r.systemdCommand(it, res.SystemdUnit)
+	case why.SourcePM2:
+		env, err := r.environ(it.Pid)
+		if err != nil {
+			return nil, fmt.Errorf("reading the environment of %s (%d) for its pm2 id: %w", it.Executable, it.Pid, err)
+		}
+		id := envValue(env, "pm_id")
+		if id == "" {
+			return nil, fmt.Errorf("%s (%d) is run by pm2, but pm_id is not in its environment", it.Executable, it.Pid)
+		}
+		cmd := ManagerCommand{Manager: "pm2", Args: []string{"pm2", "stop", id}}
+		if home := envValue(env, "PM2_HOME"); home != "" {
+			cmd.Env = []string{"PM2_HOME=" + home}
+		}
+		return &cmd, nil
+	case why.SourceSupervisor:
+		if res.Source.Name != "supervisord" {
+			return nil, fmt.Errorf("%s (%d) is supervised by %s, which gokill cannot drive", it.Executable, it.Pid, res.Source.Name)
+		}
+		env, err := r.environ(it.Pid)
+		if err != nil {
+			return nil, fmt.Errorf("reading the environment of %s (%d) for its supervisord program: %w", it.Executable, it.Pid, err)
+		}
+		name, group := envValue(env, "SUPERVISOR_PROCESS_NAME"), envValue(env, "SUPERVISOR_GROUP_NAME")
+		if name == "" {
+			return nil, fmt.Errorf("%s (%d) is run by supervisord, but SUPERVISOR_PROCESS_NAME is not in its environment", it.Executable, it.Pid)
+		}
+		if group != "" && group != name {
+			name = group + ":" + name
+		}
+		return &ManagerCommand{Manager: "supervisord", Args: []string{"supervisorctl", "stop", name}}, nil
+	}
+
+	source := string(res.Source.Type)
+	if source == "" {
+		source = string(why.SourceUnknown)
+	}
+	return nil, fmt.Errorf("%s (%d) is not run by systemd, pm2, supervisord or docker (source: %s)", it.Executable, it.Pid, source)
+}
+
+// systemdCommand stops unit, through the user manager that owns it if the
+// process's cgroup lies below user@UID.service. The user manager itself is
+// refused: stopping it ends the whole login session.
+func (r managerResolver) systemdCommand(it *Item, unit string) (*ManagerCommand, error) {
+	if unit == "" {
+		return nil, fmt.Errorf("%s (%d) is run by systemd, but its unit is unknown", it.Executable, it.Pid)
+	}
+	if strings.HasPrefix(unit, "user@") {
+		return nil, fmt.Errorf("%s (%d) belongs to the user manager %s, not to a service of its own", it.Executable, it.Pid, unit)
+	}
+	args := []string{"systemctl", "stop", unit}
+	if cgroups, err := r.cgroups(int(it.Pid)); err == nil {
+		if uid := userManagerUID(cgroups, unit); uid != "" {
+			args = []string{"systemctl", "--user", "stop", unit}
+			if uid != strconv.Itoa(r.uid) {
+				name := uid
+				if u, err := user.LookupId(uid); err == nil {
+					name = u.Username
+				}
+				args = []string{"systemctl", "--user", "--machine=" + name + "@", "stop", unit}
+			}
+		}
+	}
+	return &ManagerCommand{Manager: "systemd", Args: args}, nil
+}
+
+// userManagerUID returns the UID of the user manager (user@UID.service) that
+// unit runs under, or "" for a system unit.
+func userManagerUID(cgroups []Cgroup, unit string) string {
+	for _, cg := range cgroups {
+		segs := strings.Split(cg.Path, "/")
+		for i, seg := range segs {
+			if !strings.HasPrefix(seg, "user@") || !strings.HasSuffix(seg, ".service") {
+				continue
+			}
+			for _, below := range segs[i+1:] {
+				if below == unit {
+					return strings.TrimSuffix(strings.TrimPrefix(seg, "user@"), ".service")
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// readEnviron returns the raw environment of pid.
+func readEnviron(pid int32) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), inspectTimeout)
+	defer cancel()
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return nil, fmt.Errorf("process with pid %d not found: %w", pid, err)
+	}
+	return p.EnvironWithContext(ctx)
+}
+
+// envValue returns the value of key in env, a list of KEY=value entries.
+func envValue(env []string, key string) string {
+	for _, e := range env {
+		if k, v, ok := strings.Cut(e, "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/w31r4/gokill/internal/why"
+)
+
+func TestManagerStopCommandMapsSources(t *testing.T) {
+	cases := []struct {
+		name    string
+		item    *Item
+		result  why.AnalysisResult
+		env     []string
+		cgroup  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "system unit",
+			result: why.AnalysisResult{Source: why.Source{Type: why.SourceSystemd}, SystemdUnit: "nginx.service"},
+			cgroup: "/system.slice/nginx.service",
+			want:   "systemctl stop nginx.service",
+		},
+		{
+			name:   "own user unit",
+			result: why.AnalysisResult{Source: why.Source{Type: why.SourceSystemd}, SystemdUnit: "vite.service"},
+			cgroup: "/user.slice/user-1000.slice/user@1000.service/app.slice/vite.service",
+			want:   "systemctl --user stop vite.service",
+		},
+		{
+			name:    "user manager itself",
+			result:  why.AnalysisResult{Source: why.Source{Type: why.SourceSystemd}, SystemdUnit: "user@1000.service"},
+			wantErr: "user manager",
+		},
+		{
+			name:   "pm2",
+			result: why.AnalysisResult{Source: why.Source{Type: why.SourcePM2, Name: "pm2"}},
+			env:    []string{"PATH=/usr/bin", "pm_id=3", "PM2_HOME=/home/dev/.pm2"},
+			want:   "PM2_HOME=/home/dev/.pm2 pm2 stop 3",
+		},
+		{
+			name:   "supervisord group",
+			result: why.AnalysisResult{Source: why.Source{Type: why.SourceSupervisor, Name: "supervisord"}},
+			env:    []string{"SUPERVISOR_PROCESS_NAME=worker_01", "SUPERVISOR_GROUP_NAME=workers"},
+			want:   "supervisorctl stop workers:worker_01",
+		},
+		{
+			name:    "other supervisor",
+			result:  why.AnalysisResult{Source: why.Source{Type: why.SourceSupervisor, Name: "runit"}},
+			wantErr: "runit",
+		},
+		{
+			name:   "container",
+			result: why.AnalysisResult{Source: why.Source{Type: why.SourceDocker, Name: "4f2a"}, ContainerID: "4f2a"},
+			want:   "docker stop 4f2a",
+		},
+		{
+			name: "docker-proxy",
+			item: &Item{Pid: 42, Executable: "docker-proxy", ContainerName: "api-1"},
+			want: "docker stop api-1",
+		},
+		{
+			name:    "shell",
+			result:  why.AnalysisResult{Source: why.Source{Type: why.SourceShell, Name: "bash"}},
+			wantErr: "source: shell",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := managerResolver{
+				analyze: func(int) (*why.AnalysisResult, error) { return &tc.result, nil },
+				environ: func(int32) ([]string, error) { return tc.env, nil },
+				cgroups: func(int) ([]Cgroup, error) { return []Cgroup{{Hierarchy: "0", Path: tc.cgroup}}, nil },
+				uid:     1000,
+			}
+			it := tc.item
+			if it == nil {
+				it = &Item{Pid: 42, Executable: "app"}
+			}
+			cmd, err := r.resolve(it)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected an error mentioning %q, got %v, %v", tc.wantErr, cmd, err)
+				}
+				return
+			}
+			if err != nil || cmd.String() != tc.want {
+				t.Fatalf("got %v, %v; want %q", cmd, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestManagerCommandStringQuotes(t *testing.T) {
+	cmd := ManagerCommand{Args: []string{"supervisorctl", "stop", "my app's worker"}}
+	if got := cmd.String(); got != `supervisorctl stop 'my app'\''s worker'` {
+		t.Fatalf("String() = %s", got)
+	}
+}
+
+func TestRunManagerCommandRunsBinaryFromPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries are shell scripts")
+	}
+	dir := t.TempDir()
+	log := filepath.Join(dir, "args")
+	script := "#!/bin/sh\necho \"$PM2_HOME $*\" > " + log + "\n[ \"$1\" = fail ] && { echo 'Failed to stop: Access denied' >&2; exit 1; }\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(dir, "pm2"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	cmd := ManagerCommand{Manager: "pm2", Env: []string{"PM2_HOME=/srv/pm2"}, Args: []string{"pm2", "stop", "3"}}
+	if err := RunManagerCommand(context.Background(), cmd); err != nil {
+		t.Fatalf("RunManagerCommand: %v", err)
+	}
+	if got, _ := os.ReadFile(log); strings.TrimSpace(string(got)) != "/srv/pm2 stop 3" {
+		t.Fatalf("fake pm2 saw %q", got)
+	}
+
+	cmd.Args = []string{"pm2", "fail"}
+	err := RunManagerCommand(context.Background(), cmd)
+	if err == nil || !strings.Contains(err.Error(), "Access denied") {
+		t.Fatalf("expected the command's message in the error, got %v", err)
+	}
+}
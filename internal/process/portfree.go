@@ -0,0 +1,308 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Defaults used by FreePort when the corresponding option is zero.
+const (
+	defaultFreePortGrace        = 3 * time.Second
+	defaultFreePortTimeout      = 10 * time.Second
+	defaultFreePortPollInterval = 200 * time.Millisecond
+)
+
+// FreePortOptions tunes FreePort.
+type FreePortOptions struct {
+	// Wait makes FreePort escalate to SIGKILL after Grace and poll until the
+	// port is released, a listener respawns, or Timeout is hit. Without it
+	// the holders only receive SIGTERM.
+	Wait bool
+	// Grace is how long holders get to exit after SIGTERM before SIGKILL.
+	Grace time.Duration
+	// Timeout bounds the whole wait.
+	Timeout time.Duration
+	// PollInterval is how often the listeners are checked again.
+	PollInterval time.Duration
+	// Holders, when non-empty, are the listeners the user confirmed.
+	// FreePort then refuses with ErrHoldersChanged if any other process
+	// listens on the port by the time it starts; confirmed holders that
+	// already exited are skipped.
+	Holders []*Item
+}
+
+// ErrHoldersChanged reports that a process other than the confirmed holders
+// listens on the port, so FreePort did not signal anything.
+var ErrHoldersChanged = errors.New("the listeners changed since they were confirmed")
+
+func (o FreePortOptions) withDefaults() FreePortOptions {
+	if o.Grace <= 0 {
+		o.Grace = defaultFreePortGrace
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = defaultFreePortTimeout
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultFreePortPollInterval
+	}
+	return o
+}
+
+// PortHolder records what FreePort did to one process that held the port.
+type PortHolder struct {
+	Item *Item
+	// Action is what was sent, e.g. "SIGTERM", "SIGTERM → SIGKILL" or
+	// "docker stop web".
+	Action string
+	// Err is the error of the last action, if it failed.
+	Err error
+}
+
+// FreePortResult is the outcome of FreePort.
+type FreePortResult struct {
+	Port uint32
+	// Holders are the processes that listened on the port when FreePort
+	// started.
+	Holders []PortHolder
+	// Freed reports that nothing listened on the port when FreePort
+	// returned. It is only meaningful with FreePortOptions.Wait.
+	Freed bool
+	// Respawned lists new listeners that bound the port after the holders
+	// were signalled, e.g. a service restarted by its supervisor.
+	Respawned []*Item
+	Elapsed   time.Duration
+}
+
+// Summary is a one-line description of the result.
+func (r *FreePortResult) Summary() string {
+	if len(r.Holders) == 0 {
+		return fmt.Sprintf("nothing is listening on port %d", r.Port)
+	}
+	holders := make([]string, len(r.Holders))
+	for i, h := range r.Holders {
+		holders[i] = fmt.Sprintf("%s (pid %d)", h.Item.Executable, h.Item.Pid)
+	}
+	held := strings.Join(holders, ", ")
+
+	switch {
+	case len(r.Respawned) > 0:
+		again := make([]string, len(r.Respawned))
+		for i, it := range r.Respawned {
+			again[i] = fmt.Sprintf("%s (pid %d)", it.Executable, it.Pid)
+		}
+		return fmt.Sprintf("port %d was held by %s and respawned as %s", r.Port, held, strings.Join(again, ", "))
+	case r.Freed:
+		return fmt.Sprintf("port %d freed in %s (held by %s)", r.Port, r.Elapsed.Round(10*time.Millisecond), held)
+	case r.Elapsed > 0:
+		return fmt.Sprintf("port %d still bound after %s (held by %s)", r.Port, r.Elapsed.Round(10*time.Millisecond), held)
+	default:
+		return fmt.Sprintf("sent SIGTERM to %s on port %d", held, r.Port)
+	}
+}
+
+// FreePort terminates every process listening on port. See FreePortOptions
+// for the escalation and wait behaviour. An error is returned when the
+// listeners cannot be determined, or when they differ from opts.Holders.
+func FreePort(ctx context.Context, port uint32, opts FreePortOptions) (*FreePortResult, error) {
+	f := portFreer{
+		listeners: PortListeners,
+		signal:    SignalItem,
+		stop:      StopContainer,
+	}
+	return f.free(ctx, port, opts)
+}
+
+// portFreer holds FreePort's side effects so tests can replace them.
+type portFreer struct {
+	listeners func(ctx context.Context, port uint32) ([]*Item, error)
+	signal    func(it *Item, sig syscall.Signal) error
+	stop      func(containerName string) error
+}
+
+func (f portFreer) free(ctx context.Context, port uint32, opts FreePortOptions) (*FreePortResult, error) {
+	opts = opts.withDefaults()
+	start := time.Now()
+
+	holders, err := f.listeners(ctx, port)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.Holders) > 0 {
+		if err := checkHolders(port, opts.Holders, holders); err != nil {
+			return nil, err
+		}
+	}
+	res := &FreePortResult{Port: port}
+	if len(holders) == 0 {
+		res.Freed = true
+		return res, nil
+	}
+
+	original := make(map[Key]int, len(holders))
+	signalled := 0
+	for i, it := range holders {
+		original[it.Key()] = i
+		h := PortHolder{Item: it}
+		if it.ContainerName != "" {
+			h.Action = "docker stop " + it.ContainerName
+			h.Err = f.stop(it.ContainerName)
+		} else {
+			h.Action = SignalName(syscall.SIGTERM)
+			h.Err = f.signal(it, syscall.SIGTERM)
+		}
+		if h.Err == nil {
+			signalled++
+		}
+		res.Holders = append(res.Holders, h)
+	}
+	if !opts.Wait || signalled == 0 {
+		return res, nil
+	}
+
+	// An incomplete scan returns an error and is retried, so the port only
+	// counts as freed after a scan that finished and found nobody.
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	escalated := false
+	respawned := make(map[Key]bool)
+	for {
+		if current, err := f.listeners(ctx, port); err == nil {
+			if len(current) == 0 {
+				res.Freed = true
+				break
+			}
+			var remaining []int
+			for _, it := range current {
+				if i, ok := original[it.Key()]; ok {
+					remaining = append(remaining, i)
+				} else if !respawned[it.Key()] {
+					respawned[it.Key()] = true
+					res.Respawned = append(res.Respawned, it)
+				}
+			}
+			if len(remaining) == 0 {
+				// The original holders are gone but someone else bound the port.
+				break
+			}
+			if !escalated && time.Since(start) >= opts.Grace {
+				escalated = true
+				for _, i := range remaining {
+					h := &res.Holders[i]
+					if h.Item.ContainerName != "" {
+						continue
+					}
+					h.Action += " → " + SignalName(syscall.SIGKILL)
+					// A holder that exited since the last scan is not a failure.
+					if err := f.signal(h.Item, syscall.SIGKILL); err != nil && !isGone(err) {
+						h.Err = err
+					}
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			res.Elapsed = time.Since(start)
+			return res, nil
+		case <-time.After(opts.PollInterval):
+		}
+	}
+	res.Elapsed = time.Since(start)
+	return res, nil
+}
+
+// checkHolders returns ErrHoldersChanged if current contains a listener that
+// is not among confirmed.
+func checkHolders(port uint32, confirmed, current []*Item) error {
+	known := make(map[Key]bool, len(confirmed))
+	for _, it := range confirmed {
+		known[it.Key()] = true
+	}
+	var added []string
+	for _, it := range current {
+		if !known[it.Key()] {
+			added = append(added, fmt.Sprintf("%s (pid %d)", it.Executable, it.Pid))
+		}
+	}
+	if len(added) > 0 {
+		return fmt.Errorf("port %d: %w: now also held by %s", port, ErrHoldersChanged, strings.Join(added, ", "))
+	}
+	return nil
+}
+
+// PortListeners returns the processes listening on port, ordered by PID.
+// Every process is checked with the same listener lookup the list uses, but
+// the remaining fields are only collected for the matches. A scan cut short
+// by ctx returns ctx's error rather than a partial list.
+func PortListeners(ctx context.Context, port uint32) ([]*Item, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	numWorkers := runtime.NumCPU() * 2
+	if numWorkers > len(procs) {
+		numWorkers = len(procs)
+	}
+	jobs := make(chan *process.Process, len(procs))
+	for _, p := range procs {
+		jobs <- p
+	}
+	close(jobs)
+
+	resolver := newDockerNetworkResolver()
+	lookup := newListenerLookup()
+	var (
+		mu    sync.Mutex
+		items []*Item
+		wg    sync.WaitGroup
+	)
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				ports, public := lookup(p)
+				if !containsPort(ports, port) {
+					continue
+				}
+				item, _ := collectItem(p, nil, resolver)
+				if item == nil {
+					continue
+				}
+				item.Ports, item.PublicListener = ports, public
+				mu.Lock()
+				items = append(items, item)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Pid < items[j].Pid })
+	return items, nil
+}
+
+func containsPort(ports []uint32, port uint32) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
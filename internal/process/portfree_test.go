@@ -0,0 +1,197 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakePort simulates the listeners on one port for portFreer tests.
+type fakePort struct {
+	listeners []*Item
+	// exitOn maps a PID to the signal that makes it release the port.
+	exitOn map[int32]syscall.Signal
+	// respawn, when set, binds the port once every original holder is gone.
+	respawn *Item
+	sent    []string
+}
+
+func (f *fakePort) freer() portFreer {
+	return portFreer{
+		listeners: func(ctx context.Context, port uint32) ([]*Item, error) {
+			return append([]*Item(nil), f.listeners...), nil
+		},
+		signal: func(it *Item, sig syscall.Signal) error {
+			f.sent = append(f.sent, SignalName(sig))
+			if f.exitOn[it.Pid] == sig {
+				f.remove(it.Pid)
+			}
+			return nil
+		},
+		stop: func(name string) error {
+			f.sent = append(f.sent, "docker stop "+name)
+			return nil
+		},
+	}
+}
+
+func (f *fakePort) remove(pid int32) {
+	var kept []*Item
+	for _, it := range f.listeners {
+		if it.Pid != pid {
+			kept = append(kept, it)
+		}
+	}
+	f.listeners = kept
+	if len(kept) == 0 && f.respawn != nil {
+		f.listeners = []*Item{f.respawn}
+		f.respawn = nil
+	}
+}
+
+var fastFreePort = FreePortOptions{Wait: true, Grace: 20 * time.Millisecond, Timeout: time.Second, PollInterval: time.Millisecond}
+
+func TestFreePortTermIsEnough(t *testing.T) {
+	fp := &fakePort{
+		listeners: []*Item{NewItem(10, "node", "dev", 3000)},
+		exitOn:    map[int32]syscall.Signal{10: syscall.SIGTERM},
+	}
+	res, err := fp.freer().free(context.Background(), 3000, fastFreePort)
+	if err != nil {
+		t.Fatalf("free: %v", err)
+	}
+	if !res.Freed || len(res.Holders) != 1 || res.Holders[0].Action != "SIGTERM" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if strings.Join(fp.sent, ",") != "SIGTERM" {
+		t.Fatalf("signals sent = %v", fp.sent)
+	}
+}
+
+func TestFreePortEscalatesToKill(t *testing.T) {
+	fp := &fakePort{
+		listeners: []*Item{NewItem(10, "stubborn", "dev", 3000)},
+		exitOn:    map[int32]syscall.Signal{10: syscall.SIGKILL},
+	}
+	res, err := fp.freer().free(context.Background(), 3000, fastFreePort)
+	if err != nil {
+		t.Fatalf("free: %v", err)
+	}
+	if !res.Freed || res.Holders[0].Action != "SIGTERM → SIGKILL" {
+		t.Fatalf("expected escalation, got %+v", res.Holders)
+	}
+	if !strings.Contains(res.Summary(), "port 3000 freed") {
+		t.Fatalf("unexpected summary %q", res.Summary())
+	}
+}
+
+func TestFreePortIgnoresHolderGoneBeforeKill(t *testing.T) {
+	fp := &fakePort{listeners: []*Item{NewItem(10, "slow", "dev", 3000)}}
+	freer := fp.freer()
+	term := freer.signal
+	freer.signal = func(it *Item, sig syscall.Signal) error {
+		if sig != syscall.SIGKILL {
+			return term(it, sig)
+		}
+		// The holder exited on its own between the last scan and SIGKILL.
+		fp.sent = append(fp.sent, SignalName(sig))
+		fp.remove(it.Pid)
+		return os.ErrProcessDone
+	}
+	res, err := freer.free(context.Background(), 3000, fastFreePort)
+	if err != nil {
+		t.Fatalf("free: %v", err)
+	}
+	if !res.Freed || res.Holders[0].Err != nil {
+		t.Fatalf("expected the port to be reported freed without error, got %+v", res.Holders)
+	}
+}
+
+func TestFreePortReportsRespawn(t *testing.T) {
+	fp := &fakePort{
+		listeners: []*Item{NewItem(10, "api", "dev", 8080)},
+		exitOn:    map[int32]syscall.Signal{10: syscall.SIGTERM},
+		respawn:   NewItem(11, "api", "dev", 8080),
+	}
+	res, err := fp.freer().free(context.Background(), 8080, fastFreePort)
+	if err != nil {
+		t.Fatalf("free: %v", err)
+	}
+	if res.Freed || len(res.Respawned) != 1 || res.Respawned[0].Pid != 11 {
+		t.Fatalf("expected respawn of pid 11, got %+v", res)
+	}
+	if !strings.Contains(res.Summary(), "respawned as api (pid 11)") {
+		t.Fatalf("unexpected summary %q", res.Summary())
+	}
+}
+
+func TestFreePortTimesOut(t *testing.T) {
+	fp := &fakePort{listeners: []*Item{NewItem(10, "immortal", "dev", 3000)}}
+	opts := fastFreePort
+	opts.Timeout = 50 * time.Millisecond
+	res, err := fp.freer().free(context.Background(), 3000, opts)
+	if err != nil {
+		t.Fatalf("free: %v", err)
+	}
+	if res.Freed || res.Elapsed == 0 || !strings.Contains(res.Summary(), "still bound") {
+		t.Fatalf("expected timeout, got %+v (%s)", res, res.Summary())
+	}
+}
+
+func TestFreePortWithoutWaitOnlyTerms(t *testing.T) {
+	fp := &fakePort{listeners: []*Item{NewItem(10, "node", "dev", 3000)}}
+	res, err := fp.freer().free(context.Background(), 3000, FreePortOptions{})
+	if err != nil {
+		t.Fatalf("free: %v", err)
+	}
+	if res.Freed || strings.Join(fp.sent, ",") != "SIGTERM" {
+		t.Fatalf("expected a single SIGTERM, got %v (%+v)", fp.sent, res)
+	}
+}
+
+func TestFreePortRefusesChangedHolders(t *testing.T) {
+	confirmed := NewItem(10, "node", "dev", 3000)
+	fp := &fakePort{listeners: []*Item{confirmed, NewItem(11, "intruder", "dev", 3000)}}
+	opts := fastFreePort
+	opts.Holders = []*Item{confirmed}
+	if _, err := fp.freer().free(context.Background(), 3000, opts); !errors.Is(err, ErrHoldersChanged) {
+		t.Fatalf("expected ErrHoldersChanged, got %v", err)
+	}
+	if len(fp.sent) != 0 {
+		t.Fatalf("expected nothing to be signalled, got %v", fp.sent)
+	}
+}
+
+func TestFreePortIgnoresIncompleteScans(t *testing.T) {
+	fp := &fakePort{listeners: []*Item{NewItem(10, "node", "dev", 3000)}}
+	f := fp.freer()
+	scans := 0
+	f.listeners = func(ctx context.Context, port uint32) ([]*Item, error) {
+		if scans++; scans > 1 {
+			return nil, context.DeadlineExceeded
+		}
+		return append([]*Item(nil), fp.listeners...), nil
+	}
+	opts := fastFreePort
+	opts.Timeout = 50 * time.Millisecond
+	res, err := f.free(context.Background(), 3000, opts)
+	if err != nil {
+		t.Fatalf("free: %v", err)
+	}
+	if res.Freed {
+		t.Fatal("a scan that failed must not count as the port being freed")
+	}
+	if _, err := PortListeners(cancelledContext(), 3000); err == nil {
+		t.Fatal("expected PortListeners to report a cancelled scan")
+	}
+}
+
+func cancelledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
@@ -80,6 +80,18 @@ func getProcessListenerInfoCtx(ctx context.Context, p *process.Process) ([]uint3
 	return ports, hasPublicListener
 }
 
+// listenerLookup 返回一个进程正在监听的端口，以及其中是否有端口绑定在通配地址（0.0.0.0/::）上。
+// 每次扫描通过 `newListenerLookup` 创建一个实例：Linux 上读取一次系统套接字表，
+// 其他平台退回到逐进程调用 `connectionsLookup`。
+type listenerLookup func(p *process.Process) ([]uint32, bool)
+
+// connectionsLookup 通过 gopsutil 逐进程查询连接，每个进程受 `portScanTimeout` 限制。
+func connectionsLookup(p *process.Process) ([]uint32, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), portScanTimeout())
+	defer cancel()
+	return getProcessListenerInfoCtx(ctx, p)
+}
+
 func getProcessPortsCtx(ctx context.Context, p *process.Process) []uint32 {
 	ports, _ := getProcessListenerInfoCtx(ctx, p)
 	return ports
@@ -118,6 +130,12 @@ func shouldScanPorts() bool {
 	return s == "1" || s == "true" || s == "yes"
 }
 
+// PortScanningEnabled reports whether listening ports are collected
+// (see GOKILL_SCAN_PORTS).
+func PortScanningEnabled() bool {
+	return shouldScanPorts()
+}
+
 // portScanTimeout 函数用于获取单个进程端口扫描的超时时间。
 // 它允许用户通过环境变量 `GOKILL_PORT_TIMEOUT_MS` 来定制这个值，从而在不同的系统环境
 // 或网络条件下进行微调，以平衡扫描的彻底性和应用的响应速度。
@@ -1,18 +1,16 @@
 package process
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
 	"sort"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/shirou/gopsutil/v3/process"
-	"github.com/w31r4/gokill/internal/why"
 )
 
 // Status represents the state of a process item in the list.
@@ -25,18 +23,38 @@ const (
 	Killed
 	// Paused marks a process that has been sent a SIGSTOP signal.
 	Paused
+	// Exited marks a process that was missing from the latest snapshot, or
+	// that Terminate saw exit.
+	Exited
+	// Zombie marks a process that terminated after a kill but has not been
+	// reaped by its parent.
+	Zombie
+	// Survived marks a process that was still running when a kill gave up
+	// waiting for it.
+	Survived
 )
 
-// Item represents a process in our list.
+// Item represents a process in our list. StartTime is the zero time when it
+// could not be read. Cmdline and Cwd are empty when they cannot be read
+// (other users' processes, kernel threads). PublicListener reports that at
+// least one of Ports is bound to a wildcard address (0.0.0.0 or ::).
+// CPUPercent is the usage since the previous scan as a percentage of one
+// CPU, RSS is the resident set size in bytes.
 type Item struct {
-	Pid           int32    `json:"pid"`
-	PPid          int32    `json:"ppid"`
-	Executable    string   `json:"executable"`
-	User          string   `json:"user"`
-	StartTime     string   `json:"startTime"`
-	Status        Status   `json:"status"`
-	Ports         []uint32 `json:"ports"`
-	ContainerName string   `json:"containerName,omitempty"`
+	Pid            int32     `json:"pid"`
+	PPid           int32     `json:"ppid"`
+	Executable     string    `json:"executable"`
+	User           string    `json:"user"`
+	StartTime      time.Time `json:"startTime,omitzero"`
+	Cmdline        string    `json:"cmdline,omitempty"`
+	Cwd            string    `json:"cwd,omitempty"`
+	Status         Status    `json:"status"`
+	Ports          []uint32  `json:"ports"`
+	PublicListener bool      `json:"publicListener,omitempty"`
+	CPUPercent     float64   `json:"cpuPercent"`
+	RSS            uint64    `json:"rss"`
+	Threads        int32     `json:"threads"`
+	ContainerName  string    `json:"containerName,omitempty"`
 }
 
 // NewItem creates a new Item for testing purposes.
@@ -83,6 +101,28 @@ func NewItem(pid int, executable, user string, ports ...int) *Item {
 // 这种模式的优势在于，它将一个大的、可分解的任务（获取所有进程信息）分解成许多小任务，并利用多核 CPU 并行处理，
 // 从而极大地缩短了总体的处理时间。
 func GetProcesses() ([]*Item, []error, error) {
+	return collectProcesses(nil)
+}
+
+// Refresh takes a new snapshot like GetProcesses, but processes already in
+// prev (same PID and creation time) are not collected again: their static
+// fields are reused and only the name, command line, user, working
+// directory, parent PID and ports are re-read. A process whose name or
+// command line changed (it was seen between fork and exec) is collected
+// again from scratch. Items in prev are not modified.
+func Refresh(prev []*Item) ([]*Item, []error, error) {
+	known := make(map[Key]*Item, len(prev))
+	for _, it := range prev {
+		if it.Status != Exited && !it.StartTime.IsZero() {
+			known[it.Key()] = it
+		}
+	}
+	return collectProcesses(known)
+}
+
+// collectProcesses 是 GetProcesses 与 Refresh 共用的 Worker Pool 实现。
+// known 非空时，已知进程只重新读取可能变化的字段，见 refreshItem。
+func collectProcesses(known map[Key]*Item) ([]*Item, []error, error) {
 	// 首先，使用 gopsutil 库获取一个包含所有进程的列表。
 	procs, err := process.Processes()
 	if err != nil {
@@ -129,6 +169,13 @@ func GetProcesses() ([]*Item, []error, error) {
 	// This caches network inspection results across all workers in this scan cycle.
 	resolver := newDockerNetworkResolver()
 
+	// 是否扫描端口由环境变量控制，避免每次全量扫描带来的性能/权限问题。
+	// 端口查询器在整次扫描中共享：Linux 上系统套接字表只解析一次，各 Worker 只需读取各自进程的 fd。
+	var lookup listenerLookup
+	if shouldScanPorts() {
+		lookup = newListenerLookup()
+	}
+
 	// 这个循环创建并启动了 `numWorkers` 个 Worker Goroutine。
 	for w := 0; w < numWorkers; w++ {
 		// 每启动一个 Goroutine，WaitGroup 的计数器就加一。
@@ -140,67 +187,24 @@ func GetProcesses() ([]*Item, []error, error) {
 
 			// 每个 Worker 不断地从 `jobs` channel 中接收任务。
 			// `for range` 会一直阻塞，直到 channel 被关闭并且所有值都被接收完毕。
-			// 是否扫描端口由环境变量控制，避免每次全量扫描带来的性能/权限问题。
-			scanPorts := shouldScanPorts()
-
 			for p := range jobs {
 				// --- 单个进程信息的处理 ---
-				name, err := p.Name()
-				if err != nil {
-					// 如果获取进程名失败，则发送一个警告并跳过这个进程。
-					warnings <- fmt.Errorf("pid %d: failed to get name: %w", p.Pid, err)
-					continue
-				}
-				user, err := p.Username()
-				if err != nil {
-					user = "n/a" // 失败则使用默认值
-					warnings <- fmt.Errorf("pid %d: failed to get user: %w", p.Pid, err)
-				}
-
-				createTime, err := p.CreateTime()
-				startTime := "n/a"
-				if err == nil {
-					// 将毫秒级时间戳转换为格式化的字符串。
-					startTime = time.Unix(createTime/1000, 0).Format("15:04:05")
-				} else {
-					warnings <- fmt.Errorf("pid %d: failed to get create time: %w", p.Pid, err)
-				}
-
-				ppid, err := p.Ppid()
-				if err != nil {
-					ppid = 0
-					warnings <- fmt.Errorf("pid %d: failed to get ppid: %w", p.Pid, err)
+				if old := lookupKnown(known, p); old != nil {
+					if item := refreshItem(p, old, lookup); item != nil {
+						results <- item
+						continue
+					}
 				}
-
-				// 获取该进程监听的端口号（可选，带超时）。
-				var ports []uint32
-				if scanPorts {
-					// 为单个进程的连接采集设定一个短超时，避免卡顿拖慢整体。
-					ctx, cancel := context.WithTimeout(context.Background(), portScanTimeout())
-					ports, _ = getProcessListenerInfoCtx(ctx, p)
-					cancel()
+				item, errs := collectItem(p, lookup, resolver)
+				for _, err := range errs {
+					warnings <- err
 				}
-
-				// Docker container detection: resolve docker-proxy to container name.
-				var containerName string
-				if name == "docker-proxy" {
-					if cmdline, err := p.Cmdline(); err == nil {
-						containerName = resolver.resolve(cmdline)
-					}
+				if item == nil {
+					// 连进程名都无法获取时跳过这个进程。
+					continue
 				}
-
 				// --- 任务完成，发送结果 ---
-				// 将处理好的进程信息封装成 Item 结构体，并发送到 `results` channel。
-				results <- &Item{
-					Pid:           p.Pid,
-					PPid:          ppid,
-					Executable:    name,
-					User:          user,
-					StartTime:     startTime,
-					Status:        Alive,
-					Ports:         ports,
-					ContainerName: containerName,
-				}
+				results <- item
 			}
 		}()
 	}
@@ -227,9 +231,13 @@ func GetProcesses() ([]*Item, []error, error) {
 	// 从 `results` channel 中读取所有处理好的 `Item`。
 	// `for range` 会遍历 channel 中所有的数据，直到 channel 被关闭且为空。
 	items := make([]*Item, 0, len(procs))
+	seen := make(map[Key]bool, len(procs))
 	for item := range results {
 		items = append(items, item)
+		seen[item.Key()] = true
 	}
+	// CPU 采样器只保留本次仍存在的进程。
+	usageSampler.prune(seen)
 
 	// 等待后台聚合器读取完所有 warnings
 	warnWG.Wait()
@@ -243,426 +251,206 @@ func GetProcesses() ([]*Item, []error, error) {
 	return items, collectedWarnings, nil
 }
 
-// SendSignal sends a signal to a process by its PID.
-func SendSignal(pid int, sig syscall.Signal) error {
-	p, err := os.FindProcess(pid)
-	if err != nil {
-		return err
-	}
-	return p.Signal(sig)
-}
-
-// GetProcessDetails returns detailed information about a process.
-func GetProcessDetails(pid int) (string, error) {
-	return GetProcessDetailsWithOptions(pid, DetailsOptions{ShowEnv: runtime.GOOS == "linux"})
-}
+// collectItem 采集单个进程的列表字段。获取进程名失败时返回 nil；
+// 其余字段失败时使用默认值，并把原因作为非致命警告返回。
+// lookup 为 nil 时不采集端口。
+func collectItem(p *process.Process, lookup listenerLookup, resolver *dockerNetworkResolver) (*Item, []error) {
+	var warnings []error
 
-func GetProcessDetailsWithOptions(pid int, opts DetailsOptions) (string, error) {
-	p, err := process.NewProcess(int32(pid))
+	name, err := p.Name()
 	if err != nil {
-		return "", fmt.Errorf("process with pid %d not found: %w", pid, err)
+		return nil, []error{fmt.Errorf("pid %d: failed to get name: %w", p.Pid, err)}
 	}
-
-	details := collectProcessDetails(p)
-	ports, hasPublicListener := scanProcessPorts(p)
-
-	var b strings.Builder
-	writeBaseDetails(&b, details, ports)
-	appendWhySection(&b, pid, p, ports, hasPublicListener, opts)
-
-	return b.String(), nil
-}
-
-type processDetails struct {
-	user       string
-	name       string
-	cpuPercent float64
-	memPercent float32
-	startTime  string
-	pid        int32
-	exe        string
-	cmdline    string
-}
-
-func collectProcessDetails(p *process.Process) processDetails {
-	return processDetails{
-		user:       fetchUsername(p),
-		name:       fetchName(p),
-		cpuPercent: sampleCPUPercent(p),
-		memPercent: fetchMemoryPercent(p),
-		startTime:  fetchStartTime(p),
-		pid:        p.Pid,
-		exe:        fetchExe(p),
-		cmdline:    fetchCmdline(p),
-	}
-}
-
-func fetchUsername(p *process.Process) string {
 	user, err := p.Username()
 	if err != nil {
-		return "n/a"
-	}
-	return user
-}
-
-func sampleCPUPercent(p *process.Process) float64 {
-	// 第一次采样常为 0；进行一次短间隔的二次采样以获得更可信的数值。
-	if v, err := p.CPUPercent(); err == nil && v > 0 {
-		return v
-	}
-	time.Sleep(200 * time.Millisecond)
-	if v2, err2 := p.CPUPercent(); err2 == nil {
-		return v2
-	}
-	return 0.0
-}
-
-func fetchMemoryPercent(p *process.Process) float32 {
-	memPercent, err := p.MemoryPercent()
-	if err != nil {
-		return 0.0
-	}
-	return memPercent
-}
-
-func fetchStartTime(p *process.Process) string {
-	createTime, err := p.CreateTime() // returns millis since epoch
-	if err != nil {
-		return "n/a"
+		user = "n/a" // 失败则使用默认值
+		warnings = append(warnings, fmt.Errorf("pid %d: failed to get user: %w", p.Pid, err))
 	}
-	return time.Unix(createTime/1000, 0).Format("Jan 02 15:04")
-}
 
-func fetchName(p *process.Process) string {
-	name, err := p.Name()
-	if err != nil {
-		return "n/a"
+	// 保留真实的创建时间（毫秒精度），由界面按需格式化；获取失败时为零值。
+	var startTime time.Time
+	if createTime, err := p.CreateTime(); err == nil {
+		startTime = time.UnixMilli(createTime)
+	} else {
+		warnings = append(warnings, fmt.Errorf("pid %d: failed to get create time: %w", p.Pid, err))
 	}
-	return name
-}
 
-func fetchExe(p *process.Process) string {
-	exe, err := p.Exe()
+	ppid, err := p.Ppid()
 	if err != nil {
-		return "(permission denied or n/a)"
+		ppid = 0
+		warnings = append(warnings, fmt.Errorf("pid %d: failed to get ppid: %w", p.Pid, err))
 	}
-	return exe
-}
-
-func fetchCmdline(p *process.Process) string {
-	cmdline, err := p.Cmdline()
-	if err != nil || cmdline == "" {
-		return "(n/a)"
-	}
-	return cmdline
-}
 
-func scanProcessPorts(p *process.Process) ([]uint32, bool) {
-	if !shouldScanPorts() {
-		return nil, false
+	// 获取该进程监听的端口号（可选）。
+	var ports []uint32
+	var public bool
+	if lookup != nil {
+		ports, public = lookup(p)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), portScanTimeout())
-	defer cancel()
-	ports, public := getProcessListenerInfoCtx(ctx, p)
-	return ports, public
-}
-
-func writeBaseDetails(b *strings.Builder, details processDetails, ports []uint32) {
-	fmt.Fprintf(b, "  User:\t%s\n", details.user)
-	fmt.Fprintf(b, "  Name:\t%s\n", details.name)
-	fmt.Fprintf(b, "  %%CPU:\t%.1f\n", details.cpuPercent)
-	fmt.Fprintf(b, "  %%MEM:\t%.1f\n", details.memPercent)
-	fmt.Fprintf(b, "  Start:\t%s\n", details.startTime)
-	if len(ports) > 0 {
-		fmt.Fprintf(b, "  Ports:\t%s\n", formatPorts(ports))
-	}
-	fmt.Fprintf(b, "  Target:\t%s\n", formatTargetSummary(details.name, int(details.pid), ports))
-	fmt.Fprintf(b, "  PID:\t%d\n", details.pid)
-	fmt.Fprintf(b, "  Exe:\t%s\n", details.exe)
-	fmt.Fprintf(b, "  Command:\t%s\n", details.cmdline)
-}
-
-func appendWhySection(b *strings.Builder, pid int, p *process.Process, ports []uint32, hasPublicListener bool, opts DetailsOptions) {
-	// Analyze process ancestry and source with a 2 second timeout.
-	result, _ := why.AnalyzeWithTimeoutOptions(pid, 2*time.Second, why.AnalyzeOptions{
-		CollectEnv:  opts.ShowEnv,
-		EnvWarnings: true,
-	})
-	if result == nil {
-		return
-	}
-
-	writeWhyHeader(b)
-	appendAncestryChain(b, result)
-	appendSourceDetails(b, result)
-	appendWorkingDir(b, result)
-	appendGitDetails(b, result)
-	fmt.Fprintf(b, "  Restart Count:\t%d\n", result.RestartCount)
-	appendContextSection(b, p, ports, hasPublicListener)
-	appendWarningsSection(b, result, hasPublicListener)
-	appendVerboseSection(b, p, ports, hasPublicListener, opts)
-	appendEnvSection(b, result, opts)
-	writeWhyFooter(b)
-}
-
-func writeWhyHeader(b *strings.Builder) {
-	fmt.Fprintf(b, "\n  ─────────────────────────────────────\n")
-	fmt.Fprintf(b, "  Why It Exists:\n")
-}
 
-func writeWhyFooter(b *strings.Builder) {
-	fmt.Fprintf(b, "  ─────────────────────────────────────\n")
-}
+	// 命令行和工作目录用于区分同名进程（如多个 node/python）；读取失败（权限不足、内核线程）时留空，不记为警告。
+	cmdline, _ := p.Cmdline()
+	cwd, _ := p.Cwd()
 
-func appendAncestryChain(b *strings.Builder, result *why.AnalysisResult) {
-	if len(result.Ancestry) == 0 {
-		return
+	// Docker container detection: resolve docker-proxy to container name.
+	var containerName string
+	if name == "docker-proxy" && resolver != nil && cmdline != "" {
+		containerName = resolver.resolve(cmdline)
 	}
-	fmt.Fprintf(b, "    %s\n", why.FormatAncestryChain(result.Ancestry))
-}
 
-func appendSourceDetails(b *strings.Builder, result *why.AnalysisResult) {
-	sourceLine := formatSourceLine(result.Source)
-	fmt.Fprintf(b, "\n  Source:\t%s\n", sourceLine)
-	if result.SystemdUnit != "" {
-		fmt.Fprintf(b, "  Service:\t%s\n", result.SystemdUnit)
-	} else if needsServiceLine(result.Source) {
-		fmt.Fprintf(b, "  Service:\t%s\n", result.Source.Name)
-	}
-	if result.ContainerID != "" {
-		fmt.Fprintf(b, "  Container:\t%s\n", result.ContainerID)
+	item := &Item{
+		Pid:            p.Pid,
+		PPid:           ppid,
+		Executable:     name,
+		User:           user,
+		StartTime:      startTime,
+		Cmdline:        cmdline,
+		Cwd:            cwd,
+		Status:         Alive,
+		Ports:          ports,
+		PublicListener: public,
+		ContainerName:  containerName,
 	}
+	fillUsage(p, item)
+	return item, warnings
 }
 
-func formatSourceLine(source why.Source) string {
-	sourceStr := string(source.Type)
-	if sourceStr == "" {
-		sourceStr = string(why.SourceUnknown)
+// lookupKnown 返回 known 中与 p 身份（PID + 创建时间）相同的条目。
+func lookupKnown(known map[Key]*Item, p *process.Process) *Item {
+	if len(known) == 0 {
+		return nil
 	}
-	sourceName := source.Name
-	if source.Type == why.SourceSystemd || source.Type == why.SourceLaunchd || source.Type == why.SourceDocker {
-		sourceName = ""
-	}
-	if sourceName != "" && sourceName != sourceStr {
-		return fmt.Sprintf("%s (%s)", sourceName, sourceStr)
+	createTime, err := p.CreateTime()
+	if err != nil {
+		return nil
 	}
-	return sourceStr
+	return known[Key{Pid: p.Pid, CreateTime: createTime}]
 }
 
-func needsServiceLine(source why.Source) bool {
-	if source.Name == "" {
-		return false
+// refreshItem 复制一个已知进程的条目，只重新读取可能变化的字段（PPID 会因重新挂靠而变化，端口随时可能变化，
+// 用户和工作目录会因 setuid/chdir 变化）。进程名或命令行变了（在 fork 与 exec 之间被扫描到）时返回 nil，
+// 由调用方按新进程重新采集。
+func refreshItem(p *process.Process, old *Item, lookup listenerLookup) *Item {
+	name, err := p.Name()
+	if err != nil || name != old.Executable {
+		return nil
 	}
-	return source.Type == why.SourceSystemd || source.Type == why.SourceLaunchd
-}
-
-func appendWorkingDir(b *strings.Builder, result *why.AnalysisResult) {
-	if result.WorkingDir == "" {
-		return
+	if cmdline, _ := p.Cmdline(); cmdline != old.Cmdline {
+		return nil
 	}
-	fmt.Fprintf(b, "  Working Dir:\t%s\n", result.WorkingDir)
-}
-
-func appendGitDetails(b *strings.Builder, result *why.AnalysisResult) {
-	if result.GitRepo == "" {
-		return
+	item := *old
+	item.Status = Alive
+	if user, err := p.Username(); err == nil {
+		item.User = user
 	}
-	if result.GitBranch != "" {
-		fmt.Fprintf(b, "  Git Repo:\t%s (%s)\n", result.GitRepo, result.GitBranch)
-		return
+	if cwd, err := p.Cwd(); err == nil {
+		item.Cwd = cwd
 	}
-	fmt.Fprintf(b, "  Git Repo:\t%s\n", result.GitRepo)
-}
-
-func appendContextSection(b *strings.Builder, p *process.Process, ports []uint32, hasPublicListener bool) {
-	contextLines := buildContextLines(p, ports, hasPublicListener)
-	if len(contextLines) == 0 {
-		return
+	if ppid, err := p.Ppid(); err == nil {
+		item.PPid = ppid
 	}
-	fmt.Fprintf(b, "\n  Context:\n")
-	for _, line := range contextLines {
-		fmt.Fprintf(b, "  %s\n", line)
+	item.Ports, item.PublicListener = nil, false
+	if lookup != nil {
+		item.Ports, item.PublicListener = lookup(p)
 	}
+	item.CPUPercent, item.RSS, item.Threads = 0, 0, 0
+	fillUsage(p, &item)
+	return &item
 }
 
-func appendWarningsSection(b *strings.Builder, result *why.AnalysisResult, hasPublicListener bool) {
-	warnings := append([]string(nil), result.Warnings...)
-	if hasPublicListener {
-		warnings = append(warnings, "Process is listening on a public interface (0.0.0.0/::)")
-	}
-	warnings = dedupeStringsPreserveOrder(warnings)
-	if len(warnings) == 0 {
-		return
+// GetProcess collects a single process the same way GetProcesses does.
+// Non-fatal collection problems are returned as warnings.
+func GetProcess(pid int32) (*Item, []error, error) {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, nil, fmt.Errorf("process with pid %d not found: %w", pid, err)
 	}
-	fmt.Fprintf(b, "\n  Warnings:\n")
-	for _, warning := range warnings {
-		fmt.Fprintf(b, "  ⚠ %s\n", warning)
+	var lookup listenerLookup
+	if shouldScanPorts() {
+		lookup = newListenerLookup()
 	}
-}
-
-func dedupeStringsPreserveOrder(in []string) []string {
-	if len(in) < 2 {
-		return in
-	}
-	seen := make(map[string]struct{}, len(in))
-	out := in[:0]
-	for _, s := range in {
-		if _, ok := seen[s]; ok {
-			continue
+	item, warnings := collectItem(p, lookup, newDockerNetworkResolver())
+	if item == nil {
+		if len(warnings) > 0 {
+			return nil, nil, warnings[0]
 		}
-		seen[s] = struct{}{}
-		out = append(out, s)
+		return nil, nil, fmt.Errorf("process with pid %d not found", pid)
 	}
-	return out
+	return item, warnings, nil
 }
 
-func appendEnvSection(b *strings.Builder, result *why.AnalysisResult, opts DetailsOptions) {
-	if result == nil {
-		return
-	}
-	if !opts.ShowEnv {
-		return
-	}
-	reason := sanitizeEnvError(result.EnvError)
-	if len(result.Env) == 0 && reason == "" {
-		return
-	}
-
-	fmt.Fprintf(b, "\n  Env:\n")
-
-	if len(result.Env) == 0 {
-		fmt.Fprintf(b, "  (unavailable: %s)\n", reason)
-		return
-	}
-
-	if reason != "" {
-		fmt.Fprintf(b, "  (partial: %s)\n", reason)
-	}
-
-	// Keep the output stable and bounded.
-	env := append([]string(nil), result.Env...)
-	sort.Strings(env)
-
-	const maxEnvLines = 30
-	limit := len(env)
-	if limit > maxEnvLines {
-		limit = maxEnvLines
-	}
+// ErrPIDReused is returned by SignalItem when the PID of an item now belongs
+// to a different process than the one that was collected.
+var ErrPIDReused = errors.New("pid now belongs to a different process")
 
-	for i := 0; i < limit; i++ {
-		fmt.Fprintf(b, "  %s\n", formatEnvEntry(env[i], opts.RevealEnvSecrets))
-	}
-	if len(env) > maxEnvLines {
-		fmt.Fprintf(b, "  … (%d more)\n", len(env)-maxEnvLines)
-	}
-}
-
-func formatEnvEntry(entry string, revealSecrets bool) string {
-	key, value, ok := strings.Cut(entry, "=")
-	if !ok {
-		return sanitizeEnvEntry(entry)
-	}
-	if !revealSecrets && shouldRedactEnvKey(key) {
-		value = "<redacted>"
-	}
-	return sanitizeEnvEntry(key + "=" + value)
-}
-
-func sanitizeEnvEntry(s string) string {
-	// Make env display safe for our line-based formatter.
-	s = strings.ReplaceAll(s, "\n", "\\n")
-	s = strings.ReplaceAll(s, "\r", "\\r")
-	s = strings.ReplaceAll(s, "\t", "\\t")
-	s = strings.ReplaceAll(s, "\x1b", "") // strip ANSI ESC
-	s = truncateRunes(s, 220)
-	return s
-}
-
-func sanitizeEnvError(s string) string {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return ""
-	}
-	s = strings.ReplaceAll(s, "\n", " ")
-	s = strings.ReplaceAll(s, "\r", " ")
-	s = strings.ReplaceAll(s, "\t", " ")
-	s = strings.ReplaceAll(s, "\x1b", "")
-	s = strings.Join(strings.Fields(s), " ")
-	return truncateRunes(s, 120)
+// SendSignal sends a signal to a process by its PID. It cannot tell whether
+// the PID still belongs to the process the caller has in mind; prefer
+// SignalItem when an Item is at hand.
+func SendSignal(pid int, sig syscall.Signal) error {
+	return SignalItem(&Item{Pid: int32(pid)}, sig)
 }
 
-func formatTargetSummary(name string, pid int, ports []uint32) string {
-	trimmed := strings.TrimSpace(name)
-	if trimmed == "" || trimmed == "n/a" {
-		trimmed = "process"
-	}
-	summary := fmt.Sprintf("%s (pid %d)", trimmed, pid)
-	if len(ports) > 0 {
-		summary = fmt.Sprintf("%s, port %d", summary, ports[0])
-		if len(ports) > 1 {
-			summary = fmt.Sprintf("%s (+%d)", summary, len(ports)-1)
-		}
-	}
-	return summary
+// SignalItem sends sig to the process described by it, but only if that
+// process still holds the PID: when it.StartTime is known and the process now
+// at it.Pid started at a different time, the signal is refused with an error
+// wrapping ErrPIDReused, and it is also refused when that start time cannot
+// be read. A process that has already exited yields os.ErrProcessDone.
+//
+// On Linux the process is pinned with a pidfd before its start time is
+// checked, so it cannot exit and be replaced between the check and the
+// signal. Other platforms check first and signal right after.
+func SignalItem(it *Item, sig syscall.Signal) error {
+	return signalItem(it, sig)
 }
 
-func buildContextLines(p *process.Process, ports []uint32, hasPublicListener bool) []string {
-	var lines []string
-
-	if state := formatSocketState(ports, hasPublicListener); state != "" {
-		lines = append(lines, fmt.Sprintf("Socket State:\t%s", state))
+// checkIdentity verifies that the process at it.Pid is the one it describes.
+// Only an item without a recorded start time skips the check; if the start
+// time of the process cannot be read, the signal is refused.
+func checkIdentity(it *Item) error {
+	if it.StartTime.IsZero() {
+		return nil
 	}
-
-	if resource := formatResourceContext(p); resource != "" {
-		lines = append(lines, fmt.Sprintf("Resource:\t%s", resource))
+	p, err := process.NewProcess(it.Pid)
+	if err != nil {
+		return os.ErrProcessDone
 	}
-
-	if files := formatFileContext(p); files != "" {
-		lines = append(lines, fmt.Sprintf("Files:\t%s", files))
+	created, err := p.CreateTime()
+	if err != nil {
+		if exists, _ := process.PidExists(it.Pid); !exists {
+			return os.ErrProcessDone
+		}
 	}
-
-	return lines
+	return compareStartTime(it, created, err)
 }
 
-func formatSocketState(ports []uint32, hasPublicListener bool) string {
-	if len(ports) == 0 {
-		return ""
+// compareStartTime is checkIdentity's verdict once the start time of the
+// process at it.Pid has been read as created, or failed to read with err.
+func compareStartTime(it *Item, created int64, err error) error {
+	if err != nil {
+		return fmt.Errorf("refusing to signal pid %d: cannot read its start time to confirm it is the same process: %w", it.Pid, err)
 	}
-	state := fmt.Sprintf("listening %d", len(ports))
-	if hasPublicListener {
-		state += " (public)"
+	if created == it.StartTime.UnixMilli() {
+		return nil
 	}
-	return state
+	return fmt.Errorf("refusing to signal pid %d: %w (expected start %s, found %s)",
+		it.Pid, ErrPIDReused,
+		it.StartTime.Format("Jan 02 15:04:05"), time.UnixMilli(created).Format("Jan 02 15:04:05"))
 }
 
-func formatResourceContext(p *process.Process) string {
-	var parts []string
-
-	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
-	defer cancel()
-
-	if memInfo, err := p.MemoryInfoWithContext(ctx); err == nil && memInfo != nil && memInfo.RSS > 0 {
-		rssMB := float64(memInfo.RSS) / (1024 * 1024)
-		parts = append(parts, fmt.Sprintf("RSS %.1f MB", rssMB))
-	}
-
-	if threads, err := p.NumThreadsWithContext(ctx); err == nil && threads > 0 {
-		parts = append(parts, fmt.Sprintf("Threads %d", threads))
+// IsRunning reports whether pid still exists and is not a zombie.
+func IsRunning(pid int32) bool {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return false
 	}
-
-	if len(parts) == 0 {
-		return ""
+	status, err := p.Status()
+	if err != nil {
+		return true
 	}
-	return strings.Join(parts, " • ")
-}
-
-func formatFileContext(p *process.Process) string {
-	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
-	defer cancel()
-
-	fds, err := p.NumFDsWithContext(ctx)
-	if err != nil || fds <= 0 {
-		return ""
+	for _, s := range status {
+		if s == process.Zombie {
+			return false
+		}
 	}
-	return fmt.Sprintf("FDs %d", fds)
+	return true
 }
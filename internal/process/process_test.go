@@ -0,0 +1,61 @@
+package process
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRefreshRecollectsProcessThatExeced(t *testing.T) {
+	self, _, err := GetProcess(int32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("GetProcess: %v", err)
+	}
+
+	// The previous scan saw this PID before exec and setuid: the parent's
+	// name, command line and user.
+	stale := *self
+	stale.Executable = "bash"
+	stale.Cmdline = "bash"
+	stale.User = "somebody-else"
+
+	items, _, err := Refresh([]*Item{&stale})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	for _, it := range items {
+		if it.Key() != self.Key() {
+			continue
+		}
+		if it.Executable != self.Executable || it.Cmdline != self.Cmdline || it.User != self.User {
+			t.Fatalf("refreshed item = %q %q %q, want %q %q %q",
+				it.Executable, it.Cmdline, it.User, self.Executable, self.Cmdline, self.User)
+		}
+		return
+	}
+	t.Fatalf("pid %d missing from refreshed snapshot", os.Getpid())
+}
+
+func TestRefreshRereadsUser(t *testing.T) {
+	self, _, err := GetProcess(int32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("GetProcess: %v", err)
+	}
+
+	// Same program, but the previous scan ran before a setuid drop.
+	stale := *self
+	stale.User = "somebody-else"
+
+	items, _, err := Refresh([]*Item{&stale})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	for _, it := range items {
+		if it.Key() == self.Key() {
+			if it.User != self.User {
+				t.Fatalf("refreshed user = %q, want %q", it.User, self.User)
+			}
+			return
+		}
+	}
+	t.Fatalf("pid %d missing from refreshed snapshot", os.Getpid())
+}
@@ -0,0 +1,145 @@
+package process
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/w31r4/gokill/internal/why"
+)
+
+// respawnSlack allows for a replacement whose recorded start time is
+// slightly before the moment the kill was issued, e.g. because of clock
+// rounding.
+const respawnSlack = time.Second
+
+// Departed is a process that was killed, remembered so that a restart of it
+// can be recognised.
+type Departed struct {
+	Key        Key
+	Executable string
+	Cmdline    string
+	Ports      []uint32
+	// At is when the kill was issued.
+	At time.Time
+}
+
+// NewDeparted records it as killed at at.
+func NewDeparted(it *Item, at time.Time) Departed {
+	return Departed{
+		Key:        it.Key(),
+		Executable: it.Executable,
+		Cmdline:    it.Cmdline,
+		Ports:      slices.Clone(it.Ports),
+		At:         at,
+	}
+}
+
+// Respawn is a process that looks like a restart of a killed one.
+type Respawn struct {
+	Item *Item
+	Of   Departed
+	// Reason says what matched: "same command line" or "same port 8080".
+	Reason string
+}
+
+// Delay is the time between the kill and the start of the replacement.
+func (r Respawn) Delay() time.Duration {
+	if d := r.Item.StartTime.Sub(r.Of.At); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// FindRespawns matches items against departed processes. A match must have
+// started after the kill and have the same executable and command line (just
+// the executable when the command line was unreadable), or listen on one of
+// the departed ports. Processes with an unknown start time are never
+// matched, and each departed process is matched at most once.
+func FindRespawns(departed []Departed, items []*Item) []Respawn {
+	var out []Respawn
+	used := make([]bool, len(departed))
+	for _, it := range items {
+		if it.Status == Exited || it.StartTime.IsZero() {
+			continue
+		}
+		for i, d := range departed {
+			if used[i] || it.Key() == d.Key || it.StartTime.Before(d.At.Add(-respawnSlack)) {
+				continue
+			}
+			if reason := respawnReason(d, it); reason != "" {
+				used[i] = true
+				out = append(out, Respawn{Item: it, Of: d, Reason: reason})
+				break
+			}
+		}
+	}
+	return out
+}
+
+func respawnReason(d Departed, it *Item) string {
+	if it.Executable == d.Executable && it.Cmdline == d.Cmdline {
+		return "same command line"
+	}
+	for _, p := range d.Ports {
+		if slices.Contains(it.Ports, p) {
+			return fmt.Sprintf("same port %d", p)
+		}
+	}
+	return ""
+}
+
+// RespawnCause explains who started a respawned process.
+type RespawnCause struct {
+	// Source is the supervisor detected by the why package.
+	Source why.Source
+	// Unit is the systemd unit, when known.
+	Unit string
+	// Parent describes the parent process, e.g. "bash (4321)"; it is the
+	// explanation when no supervisor was detected.
+	Parent string
+}
+
+// Managed reports whether a supervisor that `M` can drive was detected.
+func (c RespawnCause) Managed() bool {
+	switch c.Source.Type {
+	case why.SourceSystemd, why.SourcePM2, why.SourceDocker:
+		return true
+	case why.SourceSupervisor:
+		return c.Source.Name == "supervisord"
+	}
+	return false
+}
+
+// String is a short phrase such as "systemd (nginx.service)" or "its parent
+// bash (4321)".
+func (c RespawnCause) String() string {
+	switch c.Source.Type {
+	case why.SourceUnknown, why.SourceShell, "":
+		if c.Parent != "" {
+			return "its parent " + c.Parent
+		}
+		return "an unknown parent"
+	}
+	if c.Unit != "" {
+		return fmt.Sprintf("%s (%s)", formatSourceLine(c.Source), c.Unit)
+	}
+	return formatSourceLine(c.Source)
+}
+
+// FindRespawnCause analyses pid's ancestry to find out who restarted it.
+func FindRespawnCause(pid int32) (RespawnCause, error) {
+	res, err := why.AnalyzeWithTimeoutOptions(int(pid), 2*time.Second, why.AnalyzeOptions{})
+	if res == nil {
+		if err == nil {
+			err = fmt.Errorf("no analysis result for pid %d", pid)
+		}
+		return RespawnCause{}, err
+	}
+	cause := RespawnCause{Source: res.Source, Unit: res.SystemdUnit}
+	if n := len(res.Ancestry); n >= 2 {
+		parent := res.Ancestry[n-2]
+		cause.Parent = fmt.Sprintf("%s (%d)", parent.Command, parent.PID)
+	}
+	return cause, nil
+}
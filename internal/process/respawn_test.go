@@ -0,0 +1,52 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/w31r4/gokill/internal/why"
+)
+
+func TestFindRespawnsMatchesCommandLineOrPort(t *testing.T) {
+	killedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := &Item{Pid: 10, Executable: "node", Cmdline: "node server.js", Ports: []uint32{3000}, StartTime: killedAt.Add(-time.Hour)}
+	api := &Item{Pid: 20, Executable: "api", Cmdline: "api --prod", StartTime: killedAt.Add(-time.Hour)}
+	departed := []Departed{NewDeparted(old, killedAt), NewDeparted(api, killedAt)}
+
+	items := []*Item{
+		old, // the killed process itself is never its own respawn
+		{Pid: 11, Executable: "node", Cmdline: "node server.js", StartTime: killedAt.Add(-time.Minute)}, // started before the kill
+		{Pid: 12, Executable: "node", Cmdline: "node server.js", StartTime: time.Time{}},                // unknown start
+		{Pid: 13, Executable: "bun", Cmdline: "bun run dev", Ports: []uint32{3000}, StartTime: killedAt.Add(2 * time.Second)},
+		{Pid: 21, Executable: "api", Cmdline: "api --prod", StartTime: killedAt.Add(500 * time.Millisecond)},
+		{Pid: 22, Executable: "api", Cmdline: "api --prod", StartTime: killedAt.Add(time.Second)}, // api already matched
+	}
+	got := FindRespawns(departed, items)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 respawns, got %+v", got)
+	}
+	if got[0].Item.Pid != 13 || got[0].Of.Key != old.Key() || got[0].Reason != "same port 3000" {
+		t.Fatalf("unexpected port match: %+v", got[0])
+	}
+	if got[1].Item.Pid != 21 || got[1].Reason != "same command line" || got[1].Delay() != 500*time.Millisecond {
+		t.Fatalf("unexpected command line match: %+v", got[1])
+	}
+}
+
+func TestRespawnCauseString(t *testing.T) {
+	cases := []struct {
+		cause   RespawnCause
+		want    string
+		managed bool
+	}{
+		{RespawnCause{Source: why.Source{Type: why.SourceSystemd, Name: "nginx"}, Unit: "nginx.service"}, "systemd (nginx.service)", true},
+		{RespawnCause{Source: why.Source{Type: why.SourcePM2, Name: "pm2"}}, "pm2", true},
+		{RespawnCause{Source: why.Source{Type: why.SourceSupervisor, Name: "runit"}}, "runit (supervisor)", false},
+		{RespawnCause{Source: why.Source{Type: why.SourceShell, Name: "bash"}, Parent: "bash (4321)"}, "its parent bash (4321)", false},
+	}
+	for _, tc := range cases {
+		if got := tc.cause.String(); got != tc.want || tc.cause.Managed() != tc.managed {
+			t.Fatalf("%+v: got %q managed=%v, want %q managed=%v", tc.cause, got, tc.cause.Managed(), tc.want, tc.managed)
+		}
+	}
+}
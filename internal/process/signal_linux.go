@@ -0,0 +1,60 @@
+package process
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// signalItem signals through a pidfd. The pidfd refers to the process that
+// held the PID when it was opened, so once checkIdentity has accepted it, a
+// later exit and PID reuse makes pidfd_send_signal fail with ESRCH instead of
+// reaching the new process. When pidfd_open is unavailable the
+// check-then-kill path used on other platforms is taken instead.
+func signalItem(it *Item, sig syscall.Signal) error {
+	fd, err := unix.PidfdOpen(int(it.Pid), 0)
+	if pidfdUnavailable(err) {
+		return signalAfterCheck(it, sig)
+	}
+	if err != nil {
+		return pidfdError("pidfd_open", err)
+	}
+	defer unix.Close(fd)
+
+	if err := checkIdentity(it); err != nil {
+		return err
+	}
+	return pidfdError("pidfd_send_signal", unix.PidfdSendSignal(fd, sig, nil, 0))
+}
+
+// pidfdUnavailable reports whether a pidfd_open error means the call itself
+// cannot be used: ENOSYS on kernels before 5.3, EPERM from seccomp profiles
+// that predate it. pidfd_open does not check permissions, so EPERM never
+// means the caller may not signal the process.
+func pidfdUnavailable(err error) bool {
+	return errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EPERM)
+}
+
+// pidfdError maps ESRCH to os.ErrProcessDone, as os.Process.Signal does.
+func pidfdError(call string, err error) error {
+	if errors.Is(err, unix.ESRCH) {
+		return os.ErrProcessDone
+	}
+	if err != nil {
+		return os.NewSyscallError(call, err)
+	}
+	return nil
+}
+
+func signalAfterCheck(it *Item, sig syscall.Signal) error {
+	if err := checkIdentity(it); err != nil {
+		return err
+	}
+	p, err := os.FindProcess(int(it.Pid))
+	if err != nil {
+		return err
+	}
+	return p.Signal(sig)
+}
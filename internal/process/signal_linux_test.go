@@ -0,0 +1,41 @@
+//go:build linux
+
+package process
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestPidfdUnavailable(t *testing.T) {
+	for _, errno := range []error{unix.ENOSYS, unix.EPERM} {
+		if !pidfdUnavailable(errno) {
+			t.Errorf("%v should fall back to kill(2)", errno)
+		}
+	}
+	for _, err := range []error{nil, unix.ESRCH, unix.EINVAL, unix.EMFILE} {
+		if pidfdUnavailable(err) {
+			t.Errorf("%v should not fall back to kill(2)", err)
+		}
+	}
+}
+
+func TestPidfdError(t *testing.T) {
+	if err := pidfdError("pidfd_send_signal", nil); err != nil {
+		t.Fatalf("nil error mapped to %v", err)
+	}
+	if err := pidfdError("pidfd_open", unix.ESRCH); !errors.Is(err, os.ErrProcessDone) {
+		t.Fatalf("ESRCH mapped to %v, want os.ErrProcessDone", err)
+	}
+	err := pidfdError("pidfd_send_signal", unix.EPERM)
+	if !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("EPERM from pidfd_send_signal mapped to %v, want a permission error", err)
+	}
+	var sysErr *os.SyscallError
+	if !errors.As(err, &sysErr) || sysErr.Syscall != "pidfd_send_signal" {
+		t.Fatalf("expected the syscall name to be kept, got %v", err)
+	}
+}
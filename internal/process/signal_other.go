@@ -0,0 +1,22 @@
+//go:build !linux
+
+package process
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalItem checks the start time and then signals by PID. On Windows the
+// handle opened by os.FindProcess keeps the PID from being reused; elsewhere
+// a small window remains between the check and the signal.
+func signalItem(it *Item, sig syscall.Signal) error {
+	p, err := os.FindProcess(int(it.Pid))
+	if err != nil {
+		return err
+	}
+	if err := checkIdentity(it); err != nil {
+		return err
+	}
+	return p.Signal(sig)
+}
@@ -0,0 +1,67 @@
+package process
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// namedSignal pairs a signal with the short name users type ("TERM", "HUP")
+// and a one-line description of what it usually does.
+type namedSignal struct {
+	name string
+	sig  syscall.Signal
+	desc string
+}
+
+// SignalInfo describes a signal that can be sent on the current platform.
+type SignalInfo struct {
+	Name        string // conventional name, e.g. "SIGHUP"
+	Signal      syscall.Signal
+	Description string
+}
+
+// Signals lists the signals available on the current platform, most common
+// first, for menus and help text.
+func Signals() []SignalInfo {
+	out := make([]SignalInfo, len(platformSignals))
+	for i, ns := range platformSignals {
+		out[i] = SignalInfo{Name: "SIG" + ns.name, Signal: ns.sig, Description: ns.desc}
+	}
+	return out
+}
+
+// ParseSignal accepts "TERM", "SIGTERM", "term" or a number such as "15".
+// Only signals listed for the current platform are accepted by name.
+func ParseSignal(s string) (syscall.Signal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty signal")
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n <= 0 {
+			return 0, fmt.Errorf("invalid signal number %d", n)
+		}
+		return syscall.Signal(n), nil
+	}
+
+	name := strings.TrimPrefix(strings.ToUpper(s), "SIG")
+	for _, ns := range platformSignals {
+		if ns.name == name {
+			return ns.sig, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown signal %q", s)
+}
+
+// SignalName returns the conventional name of sig, e.g. "SIGTERM".
+// Signals without a known name are rendered as "signal 42".
+func SignalName(sig syscall.Signal) string {
+	for _, ns := range platformSignals {
+		if ns.sig == sig {
+			return "SIG" + ns.name
+		}
+	}
+	return fmt.Sprintf("signal %d", int(sig))
+}
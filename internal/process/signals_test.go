@@ -0,0 +1,107 @@
+package process
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+func TestParseSignal(t *testing.T) {
+	cases := []struct {
+		in   string
+		want syscall.Signal
+	}{
+		{"TERM", syscall.SIGTERM},
+		{"sigkill", syscall.SIGKILL},
+		{" SIGTERM ", syscall.SIGTERM},
+		{"9", syscall.Signal(9)},
+	}
+	for _, tc := range cases {
+		got, err := ParseSignal(tc.in)
+		if err != nil || got != tc.want {
+			t.Fatalf("ParseSignal(%q) = %v, %v; want %v", tc.in, got, err, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"", "NOPE", "-1", "0"} {
+		if _, err := ParseSignal(bad); err == nil {
+			t.Fatalf("ParseSignal(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSignalName(t *testing.T) {
+	if got := SignalName(syscall.SIGTERM); got != "SIGTERM" {
+		t.Fatalf("SignalName(SIGTERM) = %q", got)
+	}
+	if got := SignalName(syscall.Signal(200)); got != "signal 200" {
+		t.Fatalf("SignalName(200) = %q", got)
+	}
+}
+
+func TestSignalsAreNamedAndDescribed(t *testing.T) {
+	signals := Signals()
+	if len(signals) == 0 || signals[0].Signal != syscall.SIGTERM {
+		t.Fatalf("expected SIGTERM first, got %+v", signals)
+	}
+	for _, s := range signals {
+		if s.Description == "" || SignalName(s.Signal) != s.Name {
+			t.Fatalf("signal %+v should have a description and match SignalName", s)
+		}
+	}
+}
+
+func TestSignalItemChecksStartTime(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a sleep binary")
+	}
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	defer cmd.Wait()
+	defer cmd.Process.Kill()
+
+	pid := int32(cmd.Process.Pid)
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := p.CreateTime()
+	if err != nil {
+		t.Skipf("cannot read start time: %v", err)
+	}
+
+	stale := &Item{Pid: pid, StartTime: time.UnixMilli(created).Add(-time.Hour)}
+	if err := SignalItem(stale, syscall.SIGTERM); !errors.Is(err, ErrPIDReused) {
+		t.Fatalf("signalling a PID with a different start time: err = %v, want ErrPIDReused", err)
+	}
+	if !IsRunning(pid) {
+		t.Fatal("the refused signal must not reach the process")
+	}
+
+	current := &Item{Pid: pid, StartTime: time.UnixMilli(created)}
+	if err := SignalItem(current, syscall.SIGKILL); err != nil {
+		t.Fatalf("signalling the recorded process: %v", err)
+	}
+	cmd.Wait()
+	if err := SignalItem(current, syscall.SIGKILL); !errors.Is(err, os.ErrProcessDone) {
+		t.Fatalf("signalling a reaped process: err = %v, want os.ErrProcessDone", err)
+	}
+}
+
+func TestCompareStartTimeFailsClosed(t *testing.T) {
+	it := &Item{Pid: 42, StartTime: time.UnixMilli(1_700_000_000_000)}
+	if err := compareStartTime(it, it.StartTime.UnixMilli(), nil); err != nil {
+		t.Fatalf("matching start time: %v", err)
+	}
+	if err := compareStartTime(it, 0, os.ErrPermission); err == nil || !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("an unreadable start time must refuse the signal, got %v", err)
+	}
+}
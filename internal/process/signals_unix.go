@@ -0,0 +1,18 @@
+//go:build !windows
+
+package process
+
+import "syscall"
+
+// platformSignals lists the signals that can be selected by name.
+var platformSignals = []namedSignal{
+	{name: "TERM", sig: syscall.SIGTERM, desc: "ask to terminate gracefully (the default)"},
+	{name: "KILL", sig: syscall.SIGKILL, desc: "terminate immediately; cannot be caught"},
+	{name: "HUP", sig: syscall.SIGHUP, desc: "hang up; many daemons reload their config (nginx, sshd)"},
+	{name: "INT", sig: syscall.SIGINT, desc: "interrupt, like ctrl+c in a terminal"},
+	{name: "QUIT", sig: syscall.SIGQUIT, desc: "quit; Go dumps goroutine stacks, the JVM dumps threads"},
+	{name: "USR1", sig: syscall.SIGUSR1, desc: "user-defined; e.g. nginx reopens its log files"},
+	{name: "USR2", sig: syscall.SIGUSR2, desc: "user-defined; e.g. nginx upgrades its binary"},
+	{name: "STOP", sig: syscall.SIGSTOP, desc: "pause; cannot be caught"},
+	{name: "CONT", sig: syscall.SIGCONT, desc: "resume a paused process"},
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package process
+
+import "syscall"
+
+// platformSignals lists the signals that can be selected by name.
+// Windows has no job-control or user signals; only termination is meaningful.
+var platformSignals = []namedSignal{
+	{name: "TERM", sig: syscall.SIGTERM, desc: "terminate the process"},
+	{name: "KILL", sig: syscall.SIGKILL, desc: "terminate the process immediately"},
+	{name: "INT", sig: syscall.SIGINT, desc: "interrupt, like ctrl+c in a console"},
+}
@@ -0,0 +1,148 @@
+package process
+
+import "sort"
+
+// Key identifies a process across snapshots. PIDs are reused, so the
+// creation time (Item.StartTime as Unix milliseconds, 0 when unknown) is part
+// of the identity.
+type Key struct {
+	Pid        int32
+	CreateTime int64
+}
+
+// Key returns the identity of the item.
+func (it *Item) Key() Key {
+	var created int64
+	if !it.StartTime.IsZero() {
+		created = it.StartTime.UnixMilli()
+	}
+	return Key{Pid: it.Pid, CreateTime: created}
+}
+
+// ChangeKind names what happened to a process between two snapshots.
+type ChangeKind string
+
+const (
+	// ChangeStart marks a process that is new in the later snapshot.
+	ChangeStart ChangeKind = "start"
+	// ChangeExit marks a process that is missing from the later snapshot.
+	ChangeExit ChangeKind = "exit"
+	// ChangePortOpen marks a process that started listening on ports.
+	ChangePortOpen ChangeKind = "port-open"
+	// ChangePortClose marks a process that stopped listening on ports.
+	ChangePortClose ChangeKind = "port-close"
+)
+
+// Change is one difference between two snapshots.
+type Change struct {
+	Kind ChangeKind
+	// Item is the process from the newer snapshot, or from the older one for
+	// ChangeExit.
+	Item *Item
+	// Ports lists the ports that were opened or closed; empty for start and
+	// exit.
+	Ports []uint32
+}
+
+// Diff compares two snapshots and reports exits first, then starts, then
+// port changes of processes present in both. Within each group the order of
+// the snapshot the change comes from is kept.
+func Diff(prev, next []*Item) []Change {
+	prevByKey := make(map[Key]*Item, len(prev))
+	for _, it := range prev {
+		prevByKey[it.Key()] = it
+	}
+	nextByKey := make(map[Key]*Item, len(next))
+	for _, it := range next {
+		nextByKey[it.Key()] = it
+	}
+
+	var changes []Change
+	for _, it := range prev {
+		if _, ok := nextByKey[it.Key()]; !ok {
+			changes = append(changes, Change{Kind: ChangeExit, Item: it})
+		}
+	}
+	for _, it := range next {
+		if _, ok := prevByKey[it.Key()]; !ok {
+			changes = append(changes, Change{Kind: ChangeStart, Item: it})
+		}
+	}
+	for _, it := range next {
+		old, ok := prevByKey[it.Key()]
+		if !ok {
+			continue
+		}
+		if closed := portsMissing(old.Ports, it.Ports); len(closed) > 0 {
+			changes = append(changes, Change{Kind: ChangePortClose, Item: it, Ports: closed})
+		}
+		if opened := portsMissing(it.Ports, old.Ports); len(opened) > 0 {
+			changes = append(changes, Change{Kind: ChangePortOpen, Item: it, Ports: opened})
+		}
+	}
+	return changes
+}
+
+// portsMissing returns the ports in a that are not in b.
+func portsMissing(a, b []uint32) []uint32 {
+	var out []uint32
+	for _, p := range a {
+		found := false
+		for _, q := range b {
+			if p == q {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Merge folds a fresh snapshot into existing and returns the merged list,
+// sorted like GetProcesses. Items present in both keep their pointer and
+// Status (e.g. Killed or Paused markers) and take every other field from
+// fresh. Items missing from fresh are marked Exited and kept for one more
+// merge, so the list can show what went away; after that they are dropped.
+func Merge(existing, fresh []*Item) []*Item {
+	freshByKey := make(map[Key]*Item, len(fresh))
+	for _, it := range fresh {
+		freshByKey[it.Key()] = it
+	}
+
+	out := make([]*Item, 0, len(fresh)+len(existing)/8)
+	merged := make(map[Key]bool, len(existing))
+	for _, old := range existing {
+		key := old.Key()
+		if it, ok := freshByKey[key]; ok && !merged[key] {
+			status := old.Status
+			if status == Exited {
+				status = Alive
+			}
+			*old = *it
+			old.Status = status
+			merged[key] = true
+			out = append(out, old)
+			continue
+		}
+		if old.Status != Exited {
+			old.Status = Exited
+			out = append(out, old)
+		}
+	}
+	for _, it := range fresh {
+		if !merged[it.Key()] {
+			out = append(out, it)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Executable == out[j].Executable {
+			return out[i].Pid < out[j].Pid
+		}
+		return out[i].Executable < out[j].Executable
+	})
+	return out
+}
@@ -0,0 +1,92 @@
+package process
+
+import (
+	"testing"
+	"time"
+)
+
+func snapshotItem(pid int, created int64, name string, ports ...int) *Item {
+	it := NewItem(pid, name, "test", ports...)
+	it.StartTime = time.UnixMilli(created)
+	return it
+}
+
+func TestDiffStartExitAndPorts(t *testing.T) {
+	prev := []*Item{
+		snapshotItem(10, 1000, "web", 8080),
+		snapshotItem(11, 1000, "worker"),
+		snapshotItem(12, 1000, "db", 5432, 5433),
+	}
+	next := []*Item{
+		snapshotItem(10, 1000, "web", 8080, 8443),
+		snapshotItem(12, 1000, "db", 5432),
+		snapshotItem(13, 2000, "cron"),
+	}
+
+	changes := Diff(prev, next)
+	want := []struct {
+		kind  ChangeKind
+		pid   int32
+		ports []uint32
+	}{
+		{ChangeExit, 11, nil},
+		{ChangeStart, 13, nil},
+		{ChangePortOpen, 10, []uint32{8443}},
+		{ChangePortClose, 12, []uint32{5433}},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(changes), len(want), changes)
+	}
+	for i, w := range want {
+		c := changes[i]
+		if c.Kind != w.kind || c.Item.Pid != w.pid || len(c.Ports) != len(w.ports) {
+			t.Fatalf("change %d = %s pid %d ports %v, want %s pid %d ports %v", i, c.Kind, c.Item.Pid, c.Ports, w.kind, w.pid, w.ports)
+		}
+		for j := range w.ports {
+			if c.Ports[j] != w.ports[j] {
+				t.Fatalf("change %d ports = %v, want %v", i, c.Ports, w.ports)
+			}
+		}
+	}
+}
+
+func TestDiffTreatsReusedPIDAsNewProcess(t *testing.T) {
+	prev := []*Item{snapshotItem(42, 1000, "old")}
+	next := []*Item{snapshotItem(42, 5000, "new")}
+
+	changes := Diff(prev, next)
+	if len(changes) != 2 || changes[0].Kind != ChangeExit || changes[1].Kind != ChangeStart {
+		t.Fatalf("expected exit+start for a reused PID, got %+v", changes)
+	}
+	if changes[0].Item.Executable != "old" || changes[1].Item.Executable != "new" {
+		t.Fatalf("unexpected items: %s, %s", changes[0].Item.Executable, changes[1].Item.Executable)
+	}
+}
+
+func TestMergeKeepsPointersAndStatus(t *testing.T) {
+	web := snapshotItem(10, 1000, "web", 8080)
+	web.Status = Paused
+	gone := snapshotItem(11, 1000, "gone")
+	existing := []*Item{web, gone}
+
+	fresh := []*Item{
+		snapshotItem(10, 1000, "web", 8080, 8443),
+		snapshotItem(12, 3000, "api"),
+	}
+	merged := Merge(existing, fresh)
+
+	if len(merged) != 3 || merged[0].Executable != "api" || merged[1] != gone || merged[2] != web {
+		t.Fatalf("unexpected merge order: %v", merged)
+	}
+	if web.Status != Paused || len(web.Ports) != 2 {
+		t.Fatalf("expected web to keep Paused and take fresh ports, got %+v", web)
+	}
+	if gone.Status != Exited {
+		t.Fatalf("expected missing process to be marked exited, got %v", gone.Status)
+	}
+
+	merged = Merge(merged, fresh)
+	if len(merged) != 2 {
+		t.Fatalf("expected exited process to be dropped on the next merge, got %d items", len(merged))
+	}
+}
@@ -0,0 +1,176 @@
+//go:build linux
+
+package process
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// procRoot is the procfs mount the socket scanner reads.
+var procRoot = "/proc"
+
+// tcpListen is the TCP_LISTEN state as printed in /proc/net/tcp*.
+const tcpListen = "0A"
+
+// socketEntry is one listening socket from the kernel socket tables.
+type socketEntry struct {
+	port   uint32
+	public bool
+}
+
+// socketTable maps socket inodes to listening sockets.
+type socketTable map[uint64]socketEntry
+
+// socketScanner resolves listening ports for every process of one scan.
+// The kernel tables (/proc/<pid>/net/{tcp,tcp6,udp,udp6}) are parsed once
+// per network namespace instead of once per process, and each process only
+// has its /proc/<pid>/fd links read to find which of those sockets it owns.
+type socketScanner struct {
+	mu     sync.Mutex
+	tables map[string]socketTable // keyed by the net namespace link, e.g. "net:[4026531840]"
+}
+
+// newListenerLookup returns the socket table scanner, or the per-process
+// gopsutil lookup when procfs is not available.
+func newListenerLookup() listenerLookup {
+	if _, err := os.Stat(filepath.Join(procRoot, "net", "tcp")); err != nil {
+		return connectionsLookup
+	}
+	s := &socketScanner{tables: make(map[string]socketTable)}
+	return s.listeners
+}
+
+func (s *socketScanner) listeners(p *process.Process) ([]uint32, bool) {
+	inodes := socketInodes(p.Pid)
+	if len(inodes) == 0 {
+		return nil, false
+	}
+	return s.table(p.Pid).match(inodes)
+}
+
+// table returns the socket table of pid's network namespace, loading it on
+// first use. If the namespace cannot be read, the scanner's own is used.
+func (s *socketScanner) table(pid int32) socketTable {
+	pidDir := filepath.Join(procRoot, strconv.Itoa(int(pid)))
+	ns, err := os.Readlink(filepath.Join(pidDir, "ns", "net"))
+	dir := filepath.Join(pidDir, "net")
+	if err != nil {
+		ns, dir = "", filepath.Join(procRoot, "net")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.tables[ns]; ok {
+		return t
+	}
+	t := loadSocketTable(dir)
+	s.tables[ns] = t
+	return t
+}
+
+// loadSocketTable reads the TCP and UDP tables in dir. Missing tables (e.g.
+// tcp6 with IPv6 disabled) are skipped.
+func loadSocketTable(dir string) socketTable {
+	t := make(socketTable)
+	for _, name := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		parseSocketTable(f, strings.HasPrefix(name, "tcp"), t)
+		f.Close()
+	}
+	return t
+}
+
+// parseSocketTable adds the listening sockets of one /proc/net table to t.
+// For TCP only LISTEN sockets count; every bound UDP socket counts, which
+// matches what gopsutil reports as listening ("NONE" status).
+func parseSocketTable(r io.Reader, tcp bool, t socketTable) {
+	sc := bufio.NewScanner(r)
+	sc.Scan() // header
+	for sc.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode ...
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		if tcp && fields[3] != tcpListen {
+			continue
+		}
+		addr, portHex, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		port, err := strconv.ParseUint(portHex, 16, 16)
+		if err != nil || port == 0 {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil || inode == 0 {
+			continue
+		}
+		// The address is hex in kernel byte order; only the wildcard address
+		// matters here, and that is all zeros either way.
+		t[inode] = socketEntry{port: uint32(port), public: strings.Trim(addr, "0") == ""}
+	}
+}
+
+// socketInodes returns the inodes of the sockets pid has open. Processes
+// whose fd directory cannot be read (other users, exited) have none.
+func socketInodes(pid int32) []uint64 {
+	fdDir := filepath.Join(procRoot, strconv.Itoa(int(pid)), "fd")
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return nil
+	}
+	var inodes []uint64
+	for _, e := range entries {
+		link, err := os.Readlink(filepath.Join(fdDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		// Socket links look like "socket:[12345]".
+		rest, ok := strings.CutPrefix(link, "socket:[")
+		if !ok {
+			continue
+		}
+		if inode, err := strconv.ParseUint(strings.TrimSuffix(rest, "]"), 10, 64); err == nil {
+			inodes = append(inodes, inode)
+		}
+	}
+	return inodes
+}
+
+// match returns the sorted, unique listening ports among inodes and whether
+// any of them is bound to a wildcard address.
+func (t socketTable) match(inodes []uint64) ([]uint32, bool) {
+	unique := make(map[uint32]struct{})
+	public := false
+	for _, inode := range inodes {
+		e, ok := t[inode]
+		if !ok {
+			continue
+		}
+		unique[e.port] = struct{}{}
+		public = public || e.public
+	}
+	if len(unique) == 0 {
+		return nil, false
+	}
+	ports := make([]uint32, 0, len(unique))
+	for port := range unique {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports, public
+}
@@ -0,0 +1,79 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+const procNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:0CEA 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1001 1 0000000000000000 100 0 0 10 0
+   1: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1002 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:A1B2 0100007F:1F90 01 00000000:00000000 00:00000000 00000000  1000        0 1003 1 0000000000000000 20 4 30 10 -1
+`
+
+const procNetUDP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  0: 00000000000000000000000000000000:14E9 00000000000000000000000000000000:0000 07 00000000:00000000 00:00000000 00000000   100        0 2001 2 0000000000000000 0
+`
+
+func TestParseSocketTable(t *testing.T) {
+	table := make(socketTable)
+	parseSocketTable(strings.NewReader(procNetTCP), true, table)
+	parseSocketTable(strings.NewReader(procNetUDP6), false, table)
+
+	want := socketTable{
+		1001: {port: 3306, public: false},
+		1002: {port: 8080, public: true},
+		2001: {port: 5353, public: true},
+	}
+	if len(table) != len(want) {
+		t.Fatalf("got %d sockets, want %d: %+v", len(table), len(want), table)
+	}
+	for inode, w := range want {
+		if table[inode] != w {
+			t.Fatalf("inode %d = %+v, want %+v", inode, table[inode], w)
+		}
+	}
+}
+
+func TestSocketScannerMapsInodesToProcess(t *testing.T) {
+	root := t.TempDir()
+	mustWrite := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustWrite(filepath.Join(root, "net", "tcp"), procNetTCP)
+	mustWrite(filepath.Join(root, "net", "udp6"), procNetUDP6)
+	fdDir := filepath.Join(root, "42", "fd")
+	if err := os.MkdirAll(fdDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, target := range map[string]string{"0": "/dev/null", "3": "socket:[1002]", "4": "socket:[1001]", "5": "socket:[1003]", "6": "socket:[1002]"} {
+		if err := os.Symlink(target, filepath.Join(fdDir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	old := procRoot
+	procRoot = root
+	defer func() { procRoot = old }()
+
+	lookup := newListenerLookup()
+	ports, public := lookup(&process.Process{Pid: 42})
+	if len(ports) != 2 || ports[0] != 3306 || ports[1] != 8080 || !public {
+		t.Fatalf("got ports %v public %v, want [3306 8080] true", ports, public)
+	}
+	if ports, _ := lookup(&process.Process{Pid: 43}); ports != nil {
+		t.Fatalf("expected no ports for an unreadable process, got %v", ports)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package process
+
+// newListenerLookup falls back to per-process gopsutil queries on platforms
+// without procfs socket tables.
+func newListenerLookup() listenerLookup {
+	return connectionsLookup
+}
@@ -0,0 +1,264 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Defaults used by Terminate when the corresponding option is zero.
+const (
+	defaultTerminateGrace        = 3 * time.Second
+	defaultTerminateKillWait     = 2 * time.Second
+	defaultTerminatePollInterval = 100 * time.Millisecond
+)
+
+// TerminateOptions tunes Terminate.
+type TerminateOptions struct {
+	// Grace is how long targets get to exit after SIGTERM.
+	Grace time.Duration
+	// Escalate sends SIGKILL to targets still running after Grace.
+	Escalate bool
+	// KillWait is how long to wait for targets to exit after SIGKILL.
+	KillWait time.Duration
+	// PollInterval is how often the targets are checked again.
+	PollInterval time.Duration
+}
+
+func (o TerminateOptions) withDefaults() TerminateOptions {
+	if o.Grace <= 0 {
+		o.Grace = defaultTerminateGrace
+	}
+	if o.KillWait <= 0 {
+		o.KillWait = defaultTerminateKillWait
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultTerminatePollInterval
+	}
+	return o
+}
+
+// Outcome is what became of a process that Terminate signalled.
+type Outcome int
+
+const (
+	// OutcomeExited means the process is gone, or its PID now belongs to a
+	// different process.
+	OutcomeExited Outcome = iota
+	// OutcomeZombie means the process terminated but its parent had still
+	// not reaped it when Terminate stopped waiting.
+	OutcomeZombie
+	// OutcomeAlive means the process was still running when Terminate gave
+	// up, or could not be signalled at all.
+	OutcomeAlive
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeExited:
+		return "exited"
+	case OutcomeZombie:
+		return "zombie"
+	default:
+		return "still alive"
+	}
+}
+
+// TerminateResult records what Terminate did to one process.
+type TerminateResult struct {
+	Item *Item
+	// Action is what was sent, e.g. "SIGTERM" or "SIGTERM → SIGKILL".
+	Action  string
+	Outcome Outcome
+	// Elapsed is the time from SIGTERM until the outcome was observed.
+	Elapsed time.Duration
+	// Err is the error of the last signal, if it failed.
+	Err error
+}
+
+// Summary is a one-line description of the result, without the target.
+func (r TerminateResult) Summary() string {
+	switch {
+	case r.Err != nil:
+		return fmt.Sprintf("%s failed: %v", r.Action, r.Err)
+	case r.Outcome == OutcomeExited:
+		return fmt.Sprintf("exited after %s in %s", r.Action, r.Elapsed.Round(10*time.Millisecond))
+	case r.Outcome == OutcomeZombie:
+		return fmt.Sprintf("became a zombie after %s (its parent has not reaped it)", r.Action)
+	default:
+		return fmt.Sprintf("still alive after %s (%s)", r.Action, r.Elapsed.Round(10*time.Millisecond))
+	}
+}
+
+// Terminate sends SIGTERM to every item and watches them until they exit.
+// Targets still running after the grace period get SIGKILL when
+// opts.Escalate is set. A PID whose start time no longer matches the item
+// counts as exited, so a reused PID is never mistaken for the original
+// process and never receives SIGKILL. The results are in the order of items.
+func Terminate(ctx context.Context, items []*Item, opts TerminateOptions) []TerminateResult {
+	t := terminator{
+		signal: SignalItem,
+		probe:  probeProcess,
+	}
+	return t.run(ctx, items, opts)
+}
+
+// TerminateGroup is Terminate for a whole process group: SIGTERM, and SIGKILL
+// on escalation, are sent once to the group with kill(-pgid), so members not
+// in members are reached too. members are the processes to watch and report
+// on; each still gets its own result.
+func TerminateGroup(ctx context.Context, pgid int, members []*Item, opts TerminateOptions) []TerminateResult {
+	sent := make(map[syscall.Signal]error)
+	t := terminator{
+		signal: func(_ *Item, sig syscall.Signal) error {
+			if err, ok := sent[sig]; ok {
+				return err
+			}
+			err := SignalGroup(pgid, sig)
+			sent[sig] = err
+			return err
+		},
+		probe: probeProcess,
+	}
+	return t.run(ctx, members, opts)
+}
+
+// isGone reports whether a signal failed because the target no longer
+// exists: it exited, or its PID was reused by another process.
+func isGone(err error) bool {
+	return errors.Is(err, os.ErrProcessDone) || errors.Is(err, ErrPIDReused)
+}
+
+// procState is what probeProcess found at an item's PID.
+type procState int
+
+const (
+	procGone procState = iota
+	procZombie
+	procRunning
+)
+
+// probeProcess checks whether it is still running. The start time is
+// compared when it is known, so a new process that reused the PID reads as
+// gone.
+func probeProcess(it *Item) procState {
+	p, err := process.NewProcess(it.Pid)
+	if err != nil {
+		return procGone
+	}
+	if !it.StartTime.IsZero() {
+		if created, err := p.CreateTime(); err == nil && created != it.StartTime.UnixMilli() {
+			return procGone
+		}
+	}
+	status, err := p.Status()
+	if err != nil {
+		return procRunning
+	}
+	for _, s := range status {
+		if s == process.Zombie {
+			return procZombie
+		}
+	}
+	return procRunning
+}
+
+// terminator holds Terminate's side effects so tests can replace them.
+type terminator struct {
+	signal func(it *Item, sig syscall.Signal) error
+	probe  func(it *Item) procState
+}
+
+func (t terminator) run(ctx context.Context, items []*Item, opts TerminateOptions) []TerminateResult {
+	opts = opts.withDefaults()
+	start := time.Now()
+
+	results := make([]TerminateResult, len(items))
+	var pending []int
+	for i, it := range items {
+		results[i] = TerminateResult{Item: it, Action: SignalName(syscall.SIGTERM), Outcome: OutcomeAlive}
+		if t.probe(it) == procGone {
+			// Already gone, or the PID was reused: do not signal a stranger.
+			results[i].Action = "none"
+			results[i].Outcome = OutcomeExited
+			continue
+		}
+		if err := t.signal(it, syscall.SIGTERM); err != nil {
+			if isGone(err) {
+				results[i].Outcome = OutcomeExited
+			} else {
+				results[i].Err = err
+			}
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	// A zombie has terminated but is kept pending: its parent usually reaps
+	// it moments later, so it only counts as a zombie if it is still one
+	// when the wait ends.
+	zombie := make([]bool, len(items))
+	escalated := false
+wait:
+	for len(pending) > 0 {
+		running := pending[:0]
+		onlyZombies := true
+		for _, i := range pending {
+			switch t.probe(items[i]) {
+			case procGone:
+				results[i].Outcome = OutcomeExited
+				results[i].Elapsed = time.Since(start)
+				continue
+			case procZombie:
+				zombie[i] = true
+			default:
+				zombie[i] = false
+				onlyZombies = false
+			}
+			running = append(running, i)
+		}
+		pending = running
+		if len(pending) == 0 {
+			break
+		}
+
+		elapsed := time.Since(start)
+		if !escalated && elapsed >= opts.Grace {
+			// SIGKILL does nothing to a zombie, so there is nothing left to
+			// escalate once only unreaped zombies remain.
+			if !opts.Escalate || onlyZombies {
+				break
+			}
+			escalated = true
+			for _, i := range pending {
+				if zombie[i] {
+					continue
+				}
+				results[i].Action += " → " + SignalName(syscall.SIGKILL)
+				if err := t.signal(items[i], syscall.SIGKILL); err != nil && !isGone(err) {
+					results[i].Err = err
+				}
+			}
+		} else if escalated && elapsed >= opts.Grace+opts.KillWait {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			break wait
+		case <-time.After(opts.PollInterval):
+		}
+	}
+	for _, i := range pending {
+		results[i].Elapsed = time.Since(start)
+		if zombie[i] {
+			results[i].Outcome = OutcomeZombie
+		}
+	}
+	return results
+}
@@ -0,0 +1,149 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeProcs simulates processes for terminator tests: each PID exits (or
+// turns into a zombie) once it has received one of the signals in dieOn.
+type fakeProcs struct {
+	dieOn  map[int32][]syscall.Signal
+	zombie map[int32]bool
+	got    map[int32][]syscall.Signal
+	dead   map[int32]bool
+}
+
+func newFakeProcs() *fakeProcs {
+	return &fakeProcs{dieOn: map[int32][]syscall.Signal{}, zombie: map[int32]bool{}, got: map[int32][]syscall.Signal{}, dead: map[int32]bool{}}
+}
+
+func (f *fakeProcs) terminator() terminator {
+	return terminator{
+		signal: func(it *Item, sig syscall.Signal) error {
+			if f.dead[it.Pid] && !f.zombie[it.Pid] {
+				return os.ErrProcessDone
+			}
+			f.got[it.Pid] = append(f.got[it.Pid], sig)
+			for _, s := range f.dieOn[it.Pid] {
+				if s == sig {
+					f.dead[it.Pid] = true
+				}
+			}
+			return nil
+		},
+		probe: func(it *Item) procState {
+			switch {
+			case !f.dead[it.Pid]:
+				return procRunning
+			case f.zombie[it.Pid]:
+				return procZombie
+			}
+			return procGone
+		},
+	}
+}
+
+var fastTerminate = TerminateOptions{Grace: 20 * time.Millisecond, KillWait: 20 * time.Millisecond, PollInterval: time.Millisecond}
+
+func TestTerminateOutcomes(t *testing.T) {
+	f := newFakeProcs()
+	f.dieOn[1] = []syscall.Signal{syscall.SIGTERM}
+	f.dieOn[2] = []syscall.Signal{syscall.SIGKILL}
+	f.dieOn[3] = []syscall.Signal{syscall.SIGTERM}
+	f.zombie[3] = true
+	items := []*Item{NewItem(1, "polite", "u"), NewItem(2, "stubborn", "u"), NewItem(3, "orphan", "u"), NewItem(4, "unkillable", "u")}
+
+	opts := fastTerminate
+	opts.Escalate = true
+	res := f.terminator().run(context.Background(), items, opts)
+
+	want := []struct {
+		outcome Outcome
+		action  string
+	}{
+		{OutcomeExited, "SIGTERM"},
+		{OutcomeExited, "SIGTERM → SIGKILL"},
+		{OutcomeZombie, "SIGTERM"},
+		{OutcomeAlive, "SIGTERM → SIGKILL"},
+	}
+	for i, w := range want {
+		if res[i].Outcome != w.outcome || res[i].Action != w.action || res[i].Err != nil {
+			t.Fatalf("%s: got %v via %q (err %v), want %v via %q", items[i].Executable, res[i].Outcome, res[i].Action, res[i].Err, w.outcome, w.action)
+		}
+	}
+}
+
+func TestTerminateWaitsForZombiesToBeReaped(t *testing.T) {
+	f := newFakeProcs()
+	f.dieOn[6] = []syscall.Signal{syscall.SIGTERM}
+	f.zombie[6] = true
+	tr := f.terminator()
+	probes := 0
+	zombieProbe := tr.probe
+	tr.probe = func(it *Item) procState {
+		// The parent reaps the zombie after a few polls.
+		if probes++; probes > 3 {
+			return procGone
+		}
+		return zombieProbe(it)
+	}
+	res := tr.run(context.Background(), []*Item{NewItem(6, "reaped", "u")}, fastTerminate)
+	if res[0].Outcome != OutcomeExited {
+		t.Fatalf("expected a zombie that gets reaped to count as exited, got %v", res[0].Outcome)
+	}
+}
+
+func TestTerminateWithoutEscalationLeavesSurvivors(t *testing.T) {
+	f := newFakeProcs()
+	f.dieOn[2] = []syscall.Signal{syscall.SIGKILL}
+	res := f.terminator().run(context.Background(), []*Item{NewItem(2, "stubborn", "u")}, fastTerminate)
+	if res[0].Outcome != OutcomeAlive || len(f.got[2]) != 1 {
+		t.Fatalf("expected only SIGTERM and a survivor, got %v after %v", res[0].Outcome, f.got[2])
+	}
+	if got := res[0].Summary(); !strings.HasPrefix(got, "still alive after SIGTERM (") {
+		t.Fatalf("Summary() = %q", got)
+	}
+}
+
+func TestTerminateReportsSignalErrors(t *testing.T) {
+	f := newFakeProcs()
+	tr := f.terminator()
+	tr.signal = func(*Item, syscall.Signal) error { return os.ErrPermission }
+	res := tr.run(context.Background(), []*Item{NewItem(5, "root-owned", "root")}, fastTerminate)
+	if !errors.Is(res[0].Err, os.ErrPermission) || res[0].Outcome != OutcomeAlive {
+		t.Fatalf("expected a permission error and a survivor, got %+v", res[0])
+	}
+}
+
+func TestProbeProcessChecksStartTime(t *testing.T) {
+	self := &Item{Pid: int32(os.Getpid()), StartTime: time.Unix(1, 0)}
+	if got := probeProcess(self); got != procGone {
+		t.Fatalf("a PID with a different start time should read as gone, got %v", got)
+	}
+}
+
+func TestTerminateReportsUnreapedChildAsZombie(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no zombies on windows")
+	}
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	defer cmd.Wait()
+
+	// The test process is the parent and does not reap the child until the
+	// deferred Wait, so the terminated child stays a zombie.
+	res := Terminate(context.Background(), []*Item{{Pid: int32(cmd.Process.Pid)}}, TerminateOptions{Grace: 200 * time.Millisecond, PollInterval: 10 * time.Millisecond})
+	if res[0].Outcome != OutcomeZombie {
+		t.Fatalf("expected the unreaped child to be reported as a zombie, got %v (%s)", res[0].Outcome, res[0].Summary())
+	}
+}
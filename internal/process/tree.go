@@ -0,0 +1,144 @@
+package process
+
+import "sort"
+
+// ChildrenMap indexes items by parent PID. Each child list is sorted by
+// executable name and then PID, the order used wherever a tree is rendered.
+func ChildrenMap(items []*Item) map[int32][]*Item {
+	mp := make(map[int32][]*Item)
+	for _, it := range items {
+		if it.PPid == it.Pid {
+			// Some platforms report the idle process as its own parent.
+			continue
+		}
+		mp[it.PPid] = append(mp[it.PPid], it)
+	}
+	for _, kids := range mp {
+		sort.Slice(kids, func(i, j int) bool {
+			if kids[i].Executable == kids[j].Executable {
+				return kids[i].Pid < kids[j].Pid
+			}
+			return kids[i].Executable < kids[j].Executable
+		})
+	}
+	return mp
+}
+
+// Ancestors returns the parents of item found in items, nearest first. It
+// stops at the top of the tree, at a parent that is not in items, or after
+// limit steps when limit is positive.
+func Ancestors(items []*Item, item *Item, limit int) []*Item {
+	if item == nil {
+		return nil
+	}
+	byPID := make(map[int32]*Item, len(items))
+	for _, it := range items {
+		byPID[it.Pid] = it
+	}
+
+	var chain []*Item
+	seen := map[int32]bool{item.Pid: true}
+	for cur := item; limit <= 0 || len(chain) < limit; {
+		if cur.PPid == 0 || seen[cur.PPid] {
+			break
+		}
+		parent, ok := byPID[cur.PPid]
+		if !ok {
+			break
+		}
+		seen[parent.Pid] = true
+		chain = append(chain, parent)
+		cur = parent
+	}
+	return chain
+}
+
+// Subtree returns root and all of its descendants in children (see
+// ChildrenMap), children before their parents. Signalling the result in
+// order takes a tree down leaves first, so no worker is orphaned and
+// reparented while its parent is being stopped.
+func Subtree(root *Item, children map[int32][]*Item) []*Item {
+	var out []*Item
+	seen := make(map[int32]bool)
+	var walk func(it *Item)
+	walk = func(it *Item) {
+		if seen[it.Pid] {
+			return
+		}
+		seen[it.Pid] = true
+		for _, kid := range children[it.Pid] {
+			walk(kid)
+		}
+		out = append(out, it)
+	}
+	walk(root)
+	return out
+}
+
+// Roots returns the items whose parent is not part of items, sorted like
+// ChildrenMap entries. These are the tops of the forest that items forms.
+func Roots(items []*Item) []*Item {
+	present := make(map[int32]bool, len(items))
+	for _, it := range items {
+		present[it.Pid] = true
+	}
+	var roots []*Item
+	for _, it := range items {
+		if it.PPid == it.Pid || !present[it.PPid] {
+			roots = append(roots, it)
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i].Pid < roots[j].Pid })
+	return roots
+}
+
+// TreeNode is one process in a dependency tree.
+type TreeNode struct {
+	Process  *Item       `json:"process"`
+	Children []*TreeNode `json:"children,omitempty"`
+	// Truncated reports that the node has children that were cut off by the
+	// depth limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// TreeOptions controls which processes BuildTree keeps.
+type TreeOptions struct {
+	// MaxDepth limits how many levels below the root are included; zero
+	// means unlimited.
+	MaxDepth int
+	// Keep, when set, selects the processes of interest. Subtrees with no
+	// kept process are pruned, but the path from the root to every kept
+	// process is preserved so the result is still a tree.
+	Keep func(*Item) bool
+}
+
+// BuildTree builds the tree rooted at root from the parent links in
+// children (see ChildrenMap). It returns nil if root itself is filtered out
+// and has no kept descendants.
+func BuildTree(root *Item, children map[int32][]*Item, opts TreeOptions) *TreeNode {
+	return buildTreeNode(root, children, opts, 0, map[int32]bool{})
+}
+
+func buildTreeNode(it *Item, children map[int32][]*Item, opts TreeOptions, depth int, seen map[int32]bool) *TreeNode {
+	if seen[it.Pid] {
+		return nil
+	}
+	seen[it.Pid] = true
+
+	node := &TreeNode{Process: it}
+	kids := children[it.Pid]
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		node.Truncated = len(kids) > 0
+	} else {
+		for _, kid := range kids {
+			if child := buildTreeNode(kid, children, opts, depth+1, seen); child != nil {
+				node.Children = append(node.Children, child)
+			}
+		}
+	}
+
+	if opts.Keep != nil && !opts.Keep(it) && len(node.Children) == 0 {
+		return nil
+	}
+	return node
+}
@@ -0,0 +1,110 @@
+package process
+
+import "testing"
+
+func treeFixture() []*Item {
+	items := []*Item{
+		NewItem(1, "init", "root"),
+		NewItem(10, "sshd", "root", 22),
+		NewItem(20, "bash", "alice"),
+		NewItem(31, "node", "alice", 3000),
+		NewItem(30, "make", "alice"),
+		NewItem(40, "sleep", "alice"),
+	}
+	parents := map[int32]int32{10: 1, 20: 10, 31: 20, 30: 20, 40: 30}
+	for _, it := range items {
+		it.PPid = parents[it.Pid]
+	}
+	return items
+}
+
+func TestChildrenMapSortsByNameThenPID(t *testing.T) {
+	children := ChildrenMap(treeFixture())
+	kids := children[20]
+	if len(kids) != 2 || kids[0].Executable != "make" || kids[1].Executable != "node" {
+		t.Fatalf("unexpected child order: %v, %v", kids[0].Executable, kids[1].Executable)
+	}
+}
+
+func TestAncestorsNearestFirstWithLimit(t *testing.T) {
+	items := treeFixture()
+	sleep := items[5]
+
+	chain := Ancestors(items, sleep, 0)
+	want := []int32{30, 20, 10, 1}
+	if len(chain) != len(want) {
+		t.Fatalf("got %d ancestors, want %d", len(chain), len(want))
+	}
+	for i, it := range chain {
+		if it.Pid != want[i] {
+			t.Fatalf("ancestor %d = %d, want %d", i, it.Pid, want[i])
+		}
+	}
+	if got := Ancestors(items, sleep, 2); len(got) != 2 {
+		t.Fatalf("limit 2 returned %d ancestors", len(got))
+	}
+}
+
+func TestAncestorsStopsOnCycle(t *testing.T) {
+	a := NewItem(5, "a", "u")
+	b := NewItem(6, "b", "u")
+	a.PPid, b.PPid = 6, 5
+	if got := Ancestors([]*Item{a, b}, a, 0); len(got) != 1 || got[0].Pid != 6 {
+		t.Fatalf("expected the cycle to stop after one step, got %d ancestors", len(got))
+	}
+}
+
+func TestRoots(t *testing.T) {
+	items := treeFixture()
+	orphan := NewItem(99, "orphan", "bob")
+	orphan.PPid = 12345
+	items = append(items, orphan)
+
+	roots := Roots(items)
+	if len(roots) != 2 || roots[0].Pid != 1 || roots[1].Pid != 99 {
+		t.Fatalf("unexpected roots: %v", roots)
+	}
+}
+
+func TestBuildTreeDepthLimit(t *testing.T) {
+	items := treeFixture()
+	tree := BuildTree(items[0], ChildrenMap(items), TreeOptions{MaxDepth: 2})
+
+	bash := tree.Children[0].Children[0]
+	if bash.Process.Pid != 20 || !bash.Truncated || len(bash.Children) != 0 {
+		t.Fatalf("expected bash to be truncated at depth 2: %+v", bash)
+	}
+}
+
+func TestBuildTreeKeepPrunesButKeepsPath(t *testing.T) {
+	items := treeFixture()
+	listening := func(it *Item) bool { return len(it.Ports) > 0 }
+	tree := BuildTree(items[0], ChildrenMap(items), TreeOptions{Keep: listening})
+
+	sshd := tree.Children[0]
+	if sshd.Process.Pid != 10 || len(sshd.Children) != 1 {
+		t.Fatalf("expected sshd with one kept child: %+v", sshd)
+	}
+	bash := sshd.Children[0]
+	if len(bash.Children) != 1 || bash.Children[0].Process.Pid != 31 {
+		t.Fatalf("expected make subtree to be pruned, node to remain: %+v", bash.Children)
+	}
+
+	if got := BuildTree(items[5], ChildrenMap(items), TreeOptions{Keep: listening}); got != nil {
+		t.Fatalf("expected a filtered leaf root to produce no tree, got %+v", got)
+	}
+}
+
+func TestSubtreeListsChildrenBeforeParents(t *testing.T) {
+	items := treeFixture()
+	sub := Subtree(items[2], ChildrenMap(items))
+	want := []int32{40, 30, 31, 20}
+	if len(sub) != len(want) {
+		t.Fatalf("got %d processes, want %d", len(sub), len(want))
+	}
+	for i, it := range sub {
+		if it.Pid != want[i] {
+			t.Fatalf("subtree[%d] = %d, want %d", i, it.Pid, want[i])
+		}
+	}
+}
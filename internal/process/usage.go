@@ -0,0 +1,154 @@
+package process
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// cpuSampler turns cumulative CPU times into a usage percentage by
+// remembering each process's total from the previous scan, so scans never
+// have to block for a second sample. A process seen for the first time gets
+// its lifetime average, like ps(1) reports.
+type cpuSampler struct {
+	mu   sync.Mutex
+	prev map[Key]cpuSample
+}
+
+type cpuSample struct {
+	total float64 // user + system seconds
+	at    time.Time
+}
+
+func newCPUSampler() *cpuSampler {
+	return &cpuSampler{prev: make(map[Key]cpuSample)}
+}
+
+// usageSampler is shared by every scan in the process, so the TUI refresh
+// and `gokill watch` get deltas between consecutive snapshots. Nothing else
+// records into it, or the next scan would measure a shorter interval.
+var usageSampler = newCPUSampler()
+
+// percent records total (CPU seconds) for key at now and returns the usage
+// since the previous sample. The result is a percentage of one CPU, so a
+// busy multi-threaded process can exceed 100.
+func (s *cpuSampler) percent(key Key, total float64, now time.Time) float64 {
+	s.mu.Lock()
+	prev, ok := s.prev[key]
+	s.prev[key] = cpuSample{total: total, at: now}
+	s.mu.Unlock()
+	return usagePercent(key, prev, ok, total, now)
+}
+
+// peek returns what percent would, without recording the sample.
+func (s *cpuSampler) peek(key Key, total float64, now time.Time) float64 {
+	s.mu.Lock()
+	prev, ok := s.prev[key]
+	s.mu.Unlock()
+	return usagePercent(key, prev, ok, total, now)
+}
+
+func usagePercent(key Key, prev cpuSample, ok bool, total float64, now time.Time) float64 {
+	var busy float64
+	var elapsed time.Duration
+	if ok {
+		busy, elapsed = total-prev.total, now.Sub(prev.at)
+	} else if key.CreateTime > 0 {
+		busy, elapsed = total, now.Sub(time.UnixMilli(key.CreateTime))
+	}
+	if elapsed <= 0 || busy < 0 {
+		return 0
+	}
+	return 100 * busy / elapsed.Seconds()
+}
+
+// prune forgets every process that is not in seen, so the map does not grow
+// with exited processes.
+func (s *cpuSampler) prune(seen map[Key]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.prev {
+		if !seen[key] {
+			delete(s.prev, key)
+		}
+	}
+}
+
+// fillUsage sets the CPU, RSS and thread fields of item. Fields that cannot
+// be read are left at zero.
+func fillUsage(p *process.Process, item *Item) {
+	if times, err := p.Times(); err == nil {
+		item.CPUPercent = usageSampler.percent(item.Key(), times.User+times.System, time.Now())
+	}
+	if mem, err := p.MemoryInfo(); err == nil && mem != nil {
+		item.RSS = mem.RSS
+	}
+	if threads, err := p.NumThreads(); err == nil {
+		item.Threads = threads
+	}
+}
+
+// Metrics is one live sample of a process's resource usage, as plotted by
+// the details view. IO counters are cumulative; HasIO is false when they
+// cannot be read (typically another user's process).
+type Metrics struct {
+	At         time.Time `json:"at"`
+	CPUPercent float64   `json:"cpuPercent"`
+	RSS        uint64    `json:"rss"`
+	FDs        int32     `json:"fds"`
+	Threads    int32     `json:"threads"`
+	ReadBytes  uint64    `json:"readBytes"`
+	WriteBytes uint64    `json:"writeBytes"`
+	HasIO      bool      `json:"hasIO"`
+}
+
+// MetricsSampler takes live samples for the details view. It keeps its own
+// CPU history, so sampling on an interval does not disturb the deltas of
+// the list scans running alongside it.
+type MetricsSampler struct {
+	cpu *cpuSampler
+}
+
+// NewMetricsSampler returns a sampler with no history.
+func NewMetricsSampler() *MetricsSampler {
+	return &MetricsSampler{cpu: newCPUSampler()}
+}
+
+// Sample samples pid once. CPU is measured since this sampler's previous
+// sample of the same process, so calling it on an interval yields
+// per-interval usage. When startTime is known and the process at pid started
+// at a different time, the original process is gone and an error wrapping
+// ErrPIDReused is returned instead of sampling its successor.
+func (s *MetricsSampler) Sample(pid int, startTime time.Time) (Metrics, error) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return Metrics{}, fmt.Errorf("process with pid %d not found: %w", pid, err)
+	}
+	createTime, err := p.CreateTime()
+	if err != nil {
+		return Metrics{}, fmt.Errorf("process with pid %d has exited: %w", pid, err)
+	}
+	if !startTime.IsZero() && createTime != startTime.UnixMilli() {
+		return Metrics{}, fmt.Errorf("process with pid %d has exited: %w", pid, ErrPIDReused)
+	}
+
+	m := Metrics{At: time.Now()}
+	if times, err := p.Times(); err == nil {
+		m.CPUPercent = s.cpu.percent(Key{Pid: p.Pid, CreateTime: createTime}, times.User+times.System, m.At)
+	}
+	if mem, err := p.MemoryInfo(); err == nil && mem != nil {
+		m.RSS = mem.RSS
+	}
+	if fds, err := p.NumFDs(); err == nil {
+		m.FDs = fds
+	}
+	if threads, err := p.NumThreads(); err == nil {
+		m.Threads = threads
+	}
+	if io, err := p.IOCounters(); err == nil && io != nil {
+		m.ReadBytes, m.WriteBytes, m.HasIO = io.ReadBytes, io.WriteBytes, true
+	}
+	return m, nil
+}
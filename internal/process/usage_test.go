@@ -0,0 +1,78 @@
+package process
+
+import (
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCPUSamplerDeltas(t *testing.T) {
+	s := newCPUSampler()
+	start := time.UnixMilli(1_000_000)
+	key := Key{Pid: 7, CreateTime: start.UnixMilli()}
+
+	// First sight: lifetime average, 5s of CPU over 10s of wall time.
+	if got := s.percent(key, 5, start.Add(10*time.Second)); math.Abs(got-50) > 0.01 {
+		t.Fatalf("first sample = %.2f, want 50", got)
+	}
+	// Then the delta: 2 more CPU seconds over 1s (two busy threads).
+	if got := s.percent(key, 7, start.Add(11*time.Second)); math.Abs(got-200) > 0.01 {
+		t.Fatalf("second sample = %.2f, want 200", got)
+	}
+
+	s.prune(map[Key]bool{})
+	if len(s.prev) != 0 {
+		t.Fatalf("expected prune to forget exited processes, got %d", len(s.prev))
+	}
+}
+
+func TestMetricsSamplerSamplesSelf(t *testing.T) {
+	s := NewMetricsSampler()
+	m, err := s.Sample(os.Getpid(), time.Time{})
+	if err != nil {
+		t.Fatalf("Sample: %v", err)
+	}
+	if m.At.IsZero() || m.RSS == 0 || m.Threads == 0 {
+		t.Fatalf("expected a populated sample for self, got %+v", m)
+	}
+	if _, err := s.Sample(math.MaxInt32, time.Time{}); err == nil {
+		t.Fatalf("expected an error for a missing process")
+	}
+	if _, err := s.Sample(os.Getpid(), time.Unix(1, 0)); !errors.Is(err, ErrPIDReused) {
+		t.Fatalf("expected a different start time to be reported as PID reuse, got %v", err)
+	}
+}
+
+func TestMetricsSamplerIsIndependentOfScans(t *testing.T) {
+	self, _, err := GetProcess(int32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("GetProcess: %v", err)
+	}
+	key := self.Key()
+
+	s := NewMetricsSampler()
+	m, err := s.Sample(os.Getpid(), self.StartTime)
+	if err != nil {
+		t.Fatalf("Sample: %v", err)
+	}
+	if _, _, err := GetProcesses(); err != nil {
+		t.Fatalf("GetProcesses: %v", err)
+	}
+	if _, err := GetDetails(os.Getpid(), DetailsOptions{}); err != nil {
+		t.Fatalf("GetDetails: %v", err)
+	}
+
+	// The next live sample measures from the previous live sample, not from
+	// the scan in between, and the scan's history never saw the live sample.
+	if got := s.cpu.prev[key].at; !got.Equal(m.At) {
+		t.Fatalf("live history at %v, want %v", got, m.At)
+	}
+	usageSampler.mu.Lock()
+	scanned := usageSampler.prev[key].at
+	usageSampler.mu.Unlock()
+	if scanned.Equal(m.At) || scanned.Before(m.At) {
+		t.Fatalf("scan history at %v, want the scan after the live sample at %v", scanned, m.At)
+	}
+}
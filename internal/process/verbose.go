@@ -26,7 +26,7 @@ type verboseCacheKey struct {
 }
 
 type verboseCacheEntry struct {
-	lines     []string
+	fields    []DetailField
 	expiresAt time.Time
 }
 
@@ -37,9 +37,11 @@ var verboseCache = struct {
 	items: make(map[verboseCacheKey]verboseCacheEntry),
 }
 
-func appendVerboseSection(b *strings.Builder, p *process.Process, ports []uint32, hasPublicListener bool, opts DetailsOptions) {
+// collectVerbose returns the Verbose section, or nil unless opts.Verbose is
+// set. Results are cached briefly per process so live refreshes stay cheap.
+func collectVerbose(p *process.Process, opts DetailsOptions) []DetailField {
 	if !opts.Verbose || p == nil {
-		return
+		return nil
 	}
 
 	key := verboseCacheKey{pid: p.Pid}
@@ -52,31 +54,19 @@ func appendVerboseSection(b *strings.Builder, p *process.Process, ports []uint32
 	}
 
 	now := time.Now()
-	if lines, ok := getVerboseCacheLines(key, now); ok {
-		writeVerboseSection(b, lines)
-		return
+	if fields, ok := getVerboseCacheFields(key, now); ok {
+		return fields
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), verboseCollectTimeout)
-	lines := collectVerboseLines(ctx, p, ports, hasPublicListener)
+	fields := collectVerboseFields(ctx, p)
 	cancel()
 
-	putVerboseCacheLines(key, lines, now)
-	writeVerboseSection(b, lines)
+	putVerboseCacheFields(key, fields, now)
+	return fields
 }
 
-func writeVerboseSection(b *strings.Builder, lines []string) {
-	fmt.Fprintf(b, "\n  Verbose:\n")
-	if len(lines) == 0 {
-		fmt.Fprintf(b, "  (unavailable)\n")
-		return
-	}
-	for _, line := range lines {
-		fmt.Fprintf(b, "  %s\n", line)
-	}
-}
-
-func getVerboseCacheLines(key verboseCacheKey, now time.Time) ([]string, bool) {
+func getVerboseCacheFields(key verboseCacheKey, now time.Time) ([]DetailField, bool) {
 	verboseCache.mu.Lock()
 	defer verboseCache.mu.Unlock()
 
@@ -88,10 +78,10 @@ func getVerboseCacheLines(key verboseCacheKey, now time.Time) ([]string, bool) {
 		delete(verboseCache.items, key)
 		return nil, false
 	}
-	return entry.lines, true
+	return entry.fields, true
 }
 
-func putVerboseCacheLines(key verboseCacheKey, lines []string, now time.Time) {
+func putVerboseCacheFields(key verboseCacheKey, fields []DetailField, now time.Time) {
 	verboseCache.mu.Lock()
 	defer verboseCache.mu.Unlock()
 
@@ -110,30 +100,30 @@ func putVerboseCacheLines(key verboseCacheKey, lines []string, now time.Time) {
 	}
 
 	verboseCache.items[key] = verboseCacheEntry{
-		lines:     lines,
+		fields:    fields,
 		expiresAt: now.Add(verboseCacheTTL),
 	}
 }
 
-func collectVerboseLines(ctx context.Context, p *process.Process, ports []uint32, hasPublicListener bool) []string {
-	lines := make([]string, 0, 6)
-	lines = append(lines, verboseListenerLine(ctx, p))
-	lines = append(lines, verboseMemoryLine(ctx, p))
-	lines = append(lines, verboseIOLine(ctx, p))
-	lines = append(lines, verboseFDLine(ctx, p))
-	lines = append(lines, verboseThreadsLine(ctx, p))
-	lines = append(lines, verboseChildrenLine(ctx, p))
-	return lines
+func collectVerboseFields(ctx context.Context, p *process.Process) []DetailField {
+	return []DetailField{
+		verboseListenerField(ctx, p),
+		verboseMemoryField(ctx, p),
+		verboseIOField(ctx, p),
+		verboseFDField(ctx, p),
+		verboseThreadsField(ctx, p),
+		verboseChildrenField(ctx, p),
+	}
 }
 
-func verboseListenerLine(ctx context.Context, p *process.Process) string {
+func verboseListenerField(ctx context.Context, p *process.Process) DetailField {
 	if !shouldScanPorts() {
-		return "Listen:\t(disabled: set GOKILL_SCAN_PORTS=1)"
+		return DetailField{"Listen", "(disabled: set GOKILL_SCAN_PORTS=1)"}
 	}
 
 	conns, err := p.ConnectionsWithContext(ctx)
 	if err != nil {
-		return "Listen:\t" + unavailable(err)
+		return DetailField{"Listen", unavailable(err)}
 	}
 
 	unique := make(map[string]struct{})
@@ -155,7 +145,7 @@ func verboseListenerLine(ctx context.Context, p *process.Process) string {
 	}
 
 	if len(unique) == 0 {
-		return "Listen:\t(none)"
+		return DetailField{"Listen", "(none)"}
 	}
 
 	addrs := make([]string, 0, len(unique))
@@ -173,7 +163,7 @@ func verboseListenerLine(ctx context.Context, p *process.Process) string {
 	if len(addrs) > limit {
 		val = fmt.Sprintf("%s … (+%d)", val, len(addrs)-limit)
 	}
-	return "Listen:\t" + val
+	return DetailField{"Listen", val}
 }
 
 func isListenConnStatus(status string) bool {
@@ -192,24 +182,24 @@ func formatIPPort(ip string, port uint32) string {
 	return fmt.Sprintf("%s:%d", ip, port)
 }
 
-func verboseIOLine(ctx context.Context, p *process.Process) string {
+func verboseIOField(ctx context.Context, p *process.Process) DetailField {
 	io, err := p.IOCountersWithContext(ctx)
 	if err != nil || io == nil {
-		return "IO:\t" + unavailable(err)
+		return DetailField{"IO", unavailable(err)}
 	}
-	return fmt.Sprintf(
-		"IO:\tRead %s (%d) • Write %s (%d)",
+	return DetailField{"IO", fmt.Sprintf(
+		"Read %s (%d) • Write %s (%d)",
 		formatBytesIEC(io.ReadBytes),
 		io.ReadCount,
 		formatBytesIEC(io.WriteBytes),
 		io.WriteCount,
-	)
+	)}
 }
 
-func verboseFDLine(ctx context.Context, p *process.Process) string {
+func verboseFDField(ctx context.Context, p *process.Process) DetailField {
 	fds, err := p.NumFDsWithContext(ctx)
 	if err != nil || fds < 0 {
-		return "FDs:\t" + unavailable(err)
+		return DetailField{"FDs", unavailable(err)}
 	}
 
 	soft, hard := uint64(0), uint64(0)
@@ -227,24 +217,24 @@ func verboseFDLine(ctx context.Context, p *process.Process) string {
 	if soft > 0 || hard > 0 {
 		val = fmt.Sprintf("%s (limit %d/%d)", val, soft, hard)
 	}
-	return "FDs:\t" + val
+	return DetailField{"FDs", val}
 }
 
-func verboseThreadsLine(ctx context.Context, p *process.Process) string {
+func verboseThreadsField(ctx context.Context, p *process.Process) DetailField {
 	threads, err := p.NumThreadsWithContext(ctx)
 	if err != nil || threads < 0 {
-		return "Threads:\t" + unavailable(err)
+		return DetailField{"Threads", unavailable(err)}
 	}
-	return fmt.Sprintf("Threads:\t%d", threads)
+	return DetailField{"Threads", fmt.Sprintf("%d", threads)}
 }
 
-func verboseChildrenLine(ctx context.Context, p *process.Process) string {
+func verboseChildrenField(ctx context.Context, p *process.Process) DetailField {
 	children, err := p.ChildrenWithContext(ctx)
 	if err != nil {
-		return "Children:\t" + unavailable(err)
+		return DetailField{"Children", unavailable(err)}
 	}
 	if len(children) == 0 {
-		return "Children:\t0"
+		return DetailField{"Children", "0"}
 	}
 
 	pids := make([]int32, 0, len(children))
@@ -279,7 +269,7 @@ func verboseChildrenLine(ctx context.Context, p *process.Process) string {
 	if len(pids) > limit {
 		val = fmt.Sprintf("%s … (+%d)", val, len(pids)-limit)
 	}
-	return "Children:\t" + val
+	return DetailField{"Children", val}
 }
 
 func unavailable(err error) string {
@@ -9,10 +9,10 @@ import (
 	"github.com/shirou/gopsutil/v3/process"
 )
 
-func verboseMemoryLine(ctx context.Context, p *process.Process) string {
+func verboseMemoryField(ctx context.Context, p *process.Process) DetailField {
 	mem, err := p.MemoryInfoWithContext(ctx)
 	if err != nil || mem == nil {
-		return "Memory:\t" + unavailable(err)
+		return DetailField{"Memory", unavailable(err)}
 	}
 
 	var parts []string
@@ -23,7 +23,7 @@ func verboseMemoryLine(ctx context.Context, p *process.Process) string {
 		parts = append(parts, "VMS "+formatBytesIEC(mem.VMS))
 	}
 	if len(parts) == 0 {
-		return "Memory:\t(n/a)"
+		return DetailField{"Memory", "(n/a)"}
 	}
-	return "Memory:\t" + strings.Join(parts, " • ")
+	return DetailField{"Memory", strings.Join(parts, " • ")}
 }
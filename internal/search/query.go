@@ -0,0 +1,228 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// Query is a parsed search box query. Structured terms such as `user:root`,
+// `port:8080`, `cpu>50`, `mem>1g`, `age<10m` or `status:paused` must all
+// match exactly; the remaining words form Text, which callers match fuzzily.
+type Query struct {
+	Terms []Term
+	Text  string
+}
+
+// Term is one structured filter, e.g. Field "cpu", Op ">" and Value "50".
+type Term struct {
+	Field string
+	Op    string
+	Value string
+	match func(it *process.Item, now time.Time) bool
+}
+
+func (t Term) String() string {
+	return t.Field + t.Op + t.Value
+}
+
+// Match reports whether it satisfies every structured term of q. Text is not
+// considered.
+func (q Query) Match(it *process.Item, now time.Time) bool {
+	for _, t := range q.Terms {
+		if !t.match(it, now) {
+			return false
+		}
+	}
+	return true
+}
+
+// queryFields lists the structured fields and the operators they accept.
+var queryFields = map[string]string{
+	"user":      ":",
+	"name":      ":",
+	"container": ":",
+	"status":    ":",
+	"pid":       ":",
+	"ppid":      ":",
+	"port":      ":",
+	"cpu":       "<>",
+	"mem":       "<>",
+	"age":       "<>",
+}
+
+// ParseQuery splits s into structured terms and free text. A word only counts
+// as a term when it starts with a known field followed by an operator, so
+// "http://host" or "a:b" stay free text. Terms with an invalid value are left
+// out of the query and reported in the error, so a half-typed `cpu>` does not
+// empty the list.
+func ParseQuery(s string) (Query, error) {
+	var q Query
+	var text []string
+	var errs []string
+	for _, word := range strings.Fields(s) {
+		field, op, value, ok := splitTerm(word)
+		if !ok {
+			text = append(text, word)
+			continue
+		}
+		t, err := newTerm(field, op, value)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		q.Terms = append(q.Terms, t)
+	}
+	q.Text = strings.Join(text, " ")
+	if len(errs) > 0 {
+		return q, errors.New(strings.Join(errs, "; "))
+	}
+	return q, nil
+}
+
+// splitTerm recognises "field:value", "field>value" and "field<value" for the
+// fields in queryFields.
+func splitTerm(word string) (field, op, value string, ok bool) {
+	i := strings.IndexAny(word, ":<>")
+	if i <= 0 {
+		return "", "", "", false
+	}
+	field, op, value = strings.ToLower(word[:i]), word[i:i+1], word[i+1:]
+	ops, known := queryFields[field]
+	if !known || !strings.Contains(ops, op) {
+		return "", "", "", false
+	}
+	return field, op, value, true
+}
+
+func newTerm(field, op, value string) (Term, error) {
+	t := Term{Field: field, Op: op, Value: value}
+	if value == "" {
+		return t, fmt.Errorf("%s: missing value", t)
+	}
+	switch field {
+	case "user":
+		t.match = func(it *process.Item, _ time.Time) bool { return strings.EqualFold(it.User, value) }
+	case "name":
+		t.match = func(it *process.Item, _ time.Time) bool { return containsFold(it.Executable, value) }
+	case "container":
+		t.match = func(it *process.Item, _ time.Time) bool { return containsFold(it.ContainerName, value) }
+	case "status":
+		status, err := parseStatus(value)
+		if err != nil {
+			return t, fmt.Errorf("%s: %w", t, err)
+		}
+		t.match = func(it *process.Item, _ time.Time) bool { return it.Status == status }
+	case "pid", "ppid", "port":
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return t, fmt.Errorf("%s: not a number", t)
+		}
+		switch field {
+		case "pid":
+			t.match = func(it *process.Item, _ time.Time) bool { return uint64(it.Pid) == n }
+		case "ppid":
+			t.match = func(it *process.Item, _ time.Time) bool { return uint64(it.PPid) == n }
+		default:
+			t.match = func(it *process.Item, _ time.Time) bool {
+				for _, p := range it.Ports {
+					if uint64(p) == n {
+						return true
+					}
+				}
+				return false
+			}
+		}
+	case "cpu":
+		limit, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil {
+			return t, fmt.Errorf("%s: not a percentage", t)
+		}
+		t.match = func(it *process.Item, _ time.Time) bool { return compare(it.CPUPercent, op, limit) }
+	case "mem":
+		limit, err := parseBytes(value)
+		if err != nil {
+			return t, fmt.Errorf("%s: %w", t, err)
+		}
+		t.match = func(it *process.Item, _ time.Time) bool { return compare(float64(it.RSS), op, float64(limit)) }
+	case "age":
+		limit, err := parseAge(value)
+		if err != nil {
+			return t, fmt.Errorf("%s: %w", t, err)
+		}
+		t.match = func(it *process.Item, now time.Time) bool {
+			if it.StartTime.IsZero() {
+				return false
+			}
+			return compare(float64(now.Sub(it.StartTime)), op, float64(limit))
+		}
+	}
+	return t, nil
+}
+
+func compare(v float64, op string, limit float64) bool {
+	if op == ">" {
+		return v > limit
+	}
+	return v < limit
+}
+
+func containsFold(s, sub string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+}
+
+func parseStatus(s string) (process.Status, error) {
+	switch strings.ToLower(s) {
+	case "alive", "running":
+		return process.Alive, nil
+	case "paused", "stopped":
+		return process.Paused, nil
+	case "exited":
+		return process.Exited, nil
+	case "zombie":
+		return process.Zombie, nil
+	case "survived":
+		return process.Survived, nil
+	}
+	return 0, errors.New("unknown status (want alive, paused, exited, zombie or survived)")
+}
+
+// parseBytes parses sizes such as "512", "64k", "500m" or "1.5g" using
+// binary units.
+func parseBytes(s string) (uint64, error) {
+	lower := strings.TrimSuffix(strings.ToLower(s), "b")
+	mult := 1.0
+	if n := len(lower); n > 0 {
+		if i := strings.IndexByte("kmgt", lower[n-1]); i >= 0 {
+			mult = float64(uint64(1) << (10 * (i + 1)))
+			lower = lower[:n-1]
+		}
+	}
+	v, err := strconv.ParseFloat(lower, 64)
+	if err != nil || v < 0 {
+		return 0, errors.New("not a size (e.g. 500m, 1g)")
+	}
+	return uint64(v * mult), nil
+}
+
+// parseAge parses durations such as "90s", "10m", "2h", "3d" or "1w".
+func parseAge(s string) (time.Duration, error) {
+	if n := len(s); n > 1 {
+		unit := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[s[n-1]]
+		if unit > 0 {
+			v, err := strconv.ParseFloat(s[:n-1], 64)
+			if err == nil && v >= 0 {
+				return time.Duration(v * float64(unit)), nil
+			}
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, errors.New("not a duration (e.g. 10m, 3d)")
+	}
+	return d, nil
+}
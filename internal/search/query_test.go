@@ -0,0 +1,64 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+func TestParseQuerySplitsTermsAndText(t *testing.T) {
+	q, err := ParseQuery("user:root port:8080 cpu>50 server mem>1g http://x age<10m")
+	if err != nil {
+		t.Fatalf("ParseQuery: %v", err)
+	}
+	if len(q.Terms) != 5 {
+		t.Fatalf("got %d terms, want 5: %v", len(q.Terms), q.Terms)
+	}
+	if q.Text != "server http://x" {
+		t.Fatalf("text = %q", q.Text)
+	}
+
+	q, err = ParseQuery("cpu> node port:abc")
+	if err == nil || len(q.Terms) != 0 || q.Text != "node" {
+		t.Fatalf("expected invalid terms to be reported and dropped, got %+v, %v", q, err)
+	}
+}
+
+func TestFilterStructuredTerms(t *testing.T) {
+	now := time.Now()
+	web := process.NewItem(1, "node", "alice", 8080)
+	web.CPUPercent, web.RSS, web.StartTime = 75, 2<<30, now.Add(-5*time.Minute)
+	pid8080 := process.NewItem(8080, "bash", "root")
+	pid8080.StartTime = now.Add(-48 * time.Hour)
+	paused := process.NewItem(3, "worker", "root")
+	paused.Status = process.Paused
+	api := process.NewItem(4, "docker-proxy", "root", 3000)
+	api.ContainerName = "api"
+	items := []*process.Item{web, pid8080, paused, api}
+
+	cases := map[string][]*process.Item{
+		"port:8080":                     {web},
+		"pid:8080":                      {pid8080},
+		"user:ROOT status:paused":       {paused},
+		"cpu>50 mem>1g":                 {web},
+		"mem<1g user:alice":             nil,
+		"age<10m":                       {web},
+		"age>1d":                        {pid8080},
+		"container:api":                 {api},
+		"name:NODE":                     {web},
+		"user:root work":                {paused},
+		"status:alive user:root age>1d": {pid8080},
+	}
+	for query, want := range cases {
+		got := Filter(items, Options{Query: query})
+		if len(got) != len(want) {
+			t.Fatalf("%q: got %d items, want %d", query, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("%q: item %d = pid %d, want pid %d", query, i, got[i].Pid, want[i].Pid)
+			}
+		}
+	}
+}
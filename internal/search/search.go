@@ -0,0 +1,147 @@
+// Package search implements the process matching shared by the TUI list and
+// the non-interactive CLI commands, so both answer a query the same way.
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sahilm/fuzzy"
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// Options controls how Filter narrows down a process list.
+type Options struct {
+	// Query is parsed with ParseQuery: structured terms (e.g. `port:8080`,
+	// `cpu>50`) filter exactly, and the free text is matched fuzzily against
+	// name, user, PID, container and ports, or as plain substrings against the
+	// command line and working directory.
+	Query string
+	// PortsOnly keeps only listening processes and orders them by first port.
+	PortsOnly bool
+	// User, when set, keeps only processes owned by that user (case-insensitive).
+	User string
+	// Sort orders the result on top of the match order; Descending flips it.
+	Sort       SortKey
+	Descending bool
+}
+
+// source adapts a process list to the fuzzy.Source interface.
+type source struct {
+	processes []*process.Item
+}
+
+// String returns the text the fuzzy matcher searches for item i. Name, user,
+// PID, container and ports are joined so any of them can be typed. The command
+// line and cwd are left out: a short query is a subsequence of almost any long
+// argv, so they are matched by matchesArgs instead.
+func (s source) String(i int) string {
+	p := s.processes[i]
+	base := fmt.Sprintf("%s %s %d", p.Executable, p.User, p.Pid)
+	if p.ContainerName != "" {
+		base += " " + p.ContainerName
+	}
+	if ports := PortsString(p.Ports); ports != "" {
+		base += " " + ports
+	}
+	return base
+}
+
+// matchesArgs reports whether every word of text occurs, ignoring case, in the
+// command line or working directory of p.
+func matchesArgs(p *process.Item, text string) bool {
+	if p.Cmdline == "" && p.Cwd == "" {
+		return false
+	}
+	args := strings.ToLower(p.Cmdline + " " + p.Cwd)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		if !strings.Contains(args, word) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s source) Len() int {
+	return len(s.processes)
+}
+
+// Filter returns the items matching opts. Killed items are always dropped.
+// Without free text the input order is kept; with free text the fuzzy match
+// order (best match first) is used, followed by the items matched only
+// through their command line or cwd, in input order. Invalid query terms are
+// ignored, which suits a search box being typed into; use FilterQuery to
+// reject them. Sort, if set, is applied last and breaks ties with that order.
+func Filter(items []*process.Item, opts Options) []*process.Item {
+	var result []*process.Item
+	q, _ := ParseQuery(opts.Query)
+	now := time.Now()
+
+	keep := func(p *process.Item) bool {
+		if p.Status == process.Killed {
+			return false
+		}
+		if opts.PortsOnly && len(p.Ports) == 0 {
+			return false
+		}
+		if opts.User != "" && !strings.EqualFold(p.User, opts.User) {
+			return false
+		}
+		return q.Match(p, now)
+	}
+
+	if q.Text == "" {
+		for _, p := range items {
+			if keep(p) {
+				result = append(result, p)
+			}
+		}
+	} else {
+		matched := make([]bool, len(items))
+		for _, match := range fuzzy.FindFrom(q.Text, source{processes: items}) {
+			matched[match.Index] = true
+			if p := items[match.Index]; keep(p) {
+				result = append(result, p)
+			}
+		}
+		for i, p := range items {
+			if !matched[i] && matchesArgs(p, q.Text) && keep(p) {
+				result = append(result, p)
+			}
+		}
+	}
+
+	// Ports are sorted at collection time, so the first one is the smallest.
+	if opts.PortsOnly {
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].Ports[0] < result[j].Ports[0]
+		})
+	}
+	sortItems(result, opts.Sort, opts.Descending)
+	return result
+}
+
+// FilterQuery is Filter for callers that act on the result, such as the CLI:
+// an invalid query term is returned as an error instead of being ignored, so
+// a typo cannot widen the match to every process.
+func FilterQuery(items []*process.Item, opts Options) ([]*process.Item, error) {
+	if _, err := ParseQuery(opts.Query); err != nil {
+		return nil, err
+	}
+	return Filter(items, opts), nil
+}
+
+// PortsString joins ports with spaces, e.g. "80 443".
+func PortsString(ports []uint32) string {
+	if len(ports) == 0 {
+		return ""
+	}
+	parts := make([]string, len(ports))
+	for i, port := range ports {
+		parts[i] = strconv.FormatUint(uint64(port), 10)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+func TestFilterPortsOnlySortsByPort(t *testing.T) {
+	items := []*process.Item{
+		process.NewItem(1, "web", "test", 8080),
+		process.NewItem(2, "idle", "test"),
+		process.NewItem(3, "db", "test", 5432),
+	}
+
+	got := Filter(items, Options{PortsOnly: true})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 listening processes, got %d", len(got))
+	}
+	if got[0].Pid != 3 || got[1].Pid != 1 {
+		t.Fatalf("expected port order [3 1], got [%d %d]", got[0].Pid, got[1].Pid)
+	}
+}
+
+func TestFilterDropsKilled(t *testing.T) {
+	killed := process.NewItem(1, "foo", "test")
+	killed.Status = process.Killed
+	items := []*process.Item{killed, process.NewItem(2, "foobar", "test")}
+
+	for _, query := range []string{"", "foo"} {
+		got := Filter(items, Options{Query: query})
+		if len(got) != 1 || got[0].Pid != 2 {
+			t.Fatalf("query %q: expected only pid 2, got %#v", query, got)
+		}
+	}
+}
+
+func TestFilterByUser(t *testing.T) {
+	items := []*process.Item{
+		process.NewItem(1, "node", "alice", 3000),
+		process.NewItem(2, "node", "bob", 3001),
+	}
+
+	got := Filter(items, Options{Query: "node", User: "Alice"})
+	if len(got) != 1 || got[0].Pid != 1 {
+		t.Fatalf("expected only alice's process, got %#v", got)
+	}
+}
+
+func TestFilterSortsOnTopOfMatchOrder(t *testing.T) {
+	web := process.NewItem(1, "web", "test", 8080)
+	worker := process.NewItem(2, "worker", "test")
+	db := process.NewItem(3, "db", "test", 5432)
+	web.CPUPercent, worker.CPUPercent, db.CPUPercent = 5, 80, 5
+	items := []*process.Item{web, worker, db}
+
+	got := Filter(items, Options{Sort: SortCPU, Descending: true})
+	if got[0] != worker || got[1] != web || got[2] != db {
+		t.Fatalf("expected [worker web db], got [%s %s %s]", got[0].Executable, got[1].Executable, got[2].Executable)
+	}
+
+	got = Filter(items, Options{Sort: SortPort, Descending: true})
+	if got[0] != web || got[1] != db || got[2] != worker {
+		t.Fatalf("expected [web db worker], got [%s %s %s]", got[0].Executable, got[1].Executable, got[2].Executable)
+	}
+
+	// With a query only the matches are sorted.
+	got = Filter(items, Options{Query: "w", Sort: SortPID, Descending: true})
+	if len(got) != 2 || got[0] != worker || got[1] != web {
+		t.Fatalf("expected [worker web], got %d items", len(got))
+	}
+
+	if SortPort.Next() != SortNone || !SortMemory.DefaultDescending() || SortPID.DefaultDescending() {
+		t.Fatalf("unexpected sort key cycling")
+	}
+}
+
+func TestFilterMatchesCommandLine(t *testing.T) {
+	server := process.NewItem(1, "node", "dev")
+	server.Cmdline = "node /srv/app/server.js"
+	jest := process.NewItem(2, "node", "dev")
+	jest.Cmdline = "node /srv/app/node_modules/.bin/jest --watch"
+	jest.Cwd = "/srv/app"
+
+	got := Filter([]*process.Item{server, jest}, Options{Query: "jest"})
+	if len(got) != 1 || got[0] != jest {
+		t.Fatalf("expected only the jest process, got %d items", len(got))
+	}
+	got = Filter([]*process.Item{server, jest}, Options{Query: "server.js"})
+	if len(got) != 1 || got[0] != server {
+		t.Fatalf("expected only the server process, got %d items", len(got))
+	}
+
+	// A short query must not match as a scattered subsequence of the argv.
+	shell := process.NewItem(3, "bash", "dev")
+	shell.Cmdline, shell.Cwd = "/usr/bin/bash --noprofile", "/home/dev/payments"
+	got = Filter([]*process.Item{server, jest, shell}, Options{Query: "py"})
+	if len(got) != 0 {
+		t.Fatalf("expected no match for py, got %s", got[0].Executable)
+	}
+}
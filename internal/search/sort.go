@@ -0,0 +1,81 @@
+package search
+
+import (
+	"sort"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// SortKey selects the column Filter orders its result by.
+type SortKey int
+
+const (
+	// SortNone keeps the input order, or the fuzzy match order with a query.
+	SortNone SortKey = iota
+	SortCPU
+	SortMemory
+	SortPID
+	SortStartTime
+	SortUser
+	SortPort
+)
+
+var sortKeyNames = [...]string{"default", "cpu", "mem", "pid", "start", "user", "port"}
+
+func (k SortKey) String() string {
+	if k < 0 || int(k) >= len(sortKeyNames) {
+		return "unknown"
+	}
+	return sortKeyNames[k]
+}
+
+// Next returns the key after k, wrapping back to SortNone, so a UI can cycle
+// through every mode with one key.
+func (k SortKey) Next() SortKey {
+	return (k + 1) % SortKey(len(sortKeyNames))
+}
+
+// DefaultDescending reports the natural direction of k: usage columns put the
+// heaviest processes first, everything else sorts ascending.
+func (k SortKey) DefaultDescending() bool {
+	return k == SortCPU || k == SortMemory
+}
+
+// sortItems orders items by key. The sort is stable, so items that compare
+// equal keep their previous (e.g. fuzzy match) order. Processes without a
+// port always go last when sorting by port.
+func sortItems(items []*process.Item, key SortKey, descending bool) {
+	var less func(a, b *process.Item) bool
+	switch key {
+	case SortCPU:
+		less = func(a, b *process.Item) bool { return a.CPUPercent < b.CPUPercent }
+	case SortMemory:
+		less = func(a, b *process.Item) bool { return a.RSS < b.RSS }
+	case SortPID:
+		less = func(a, b *process.Item) bool { return a.Pid < b.Pid }
+	case SortStartTime:
+		less = func(a, b *process.Item) bool { return a.StartTime.Before(b.StartTime) }
+	case SortUser:
+		less = func(a, b *process.Item) bool { return a.User < b.User }
+	case SortPort:
+		sort.SliceStable(items, func(i, j int) bool {
+			a, b := items[i], items[j]
+			if len(a.Ports) == 0 || len(b.Ports) == 0 {
+				return len(a.Ports) > 0 && len(b.Ports) == 0
+			}
+			if descending {
+				return a.Ports[0] > b.Ports[0]
+			}
+			return a.Ports[0] < b.Ports[0]
+		})
+		return
+	default:
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if descending {
+			return less(items[j], items[i])
+		}
+		return less(items[i], items[j])
+	})
+}
@@ -2,11 +2,12 @@ package tui
 
 import (
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/search"
 )
 
 // dependency.go 文件包含了实现“依赖树视图”（T-Mode）的所有核心逻辑。
@@ -122,18 +123,9 @@ func (b *depLineBuilder) walk(pid int32, prefix string, depth int) {
 	}
 }
 
+// sortedChildren 返回 pid 的直接子进程；process.ChildrenMap 已按名称和PID排好序。
 func (b *depLineBuilder) sortedChildren(pid int32) []*process.Item {
-	kids := b.childrenMap[pid]
-	if len(kids) == 0 {
-		return nil
-	}
-	sort.Slice(kids, func(i, j int) bool {
-		if kids[i].Executable == kids[j].Executable {
-			return kids[i].Pid < kids[j].Pid
-		}
-		return kids[i].Executable < kids[j].Executable
-	})
-	return kids
+	return b.childrenMap[pid]
 }
 
 func (b *depLineBuilder) pageLimit(pid int32, total int) int {
@@ -202,8 +194,12 @@ func applyDepFilters(m model, lines []depLine) []depLine {
 		return lines
 	}
 
-	term := strings.TrimSpace(m.textInput.Value())
-	hasTerm := term != ""
+	// 与主列表共用同一个查询解析器：结构化条件（如 `port:8080`、`cpu>50`）精确过滤，
+	// 剩余的自由文本按原来的方式匹配行文本或 PID。
+	query, _ := search.ParseQuery(m.textInput.Value())
+	term := query.Text
+	hasTerm := strings.TrimSpace(m.textInput.Value()) != ""
+	now := time.Now()
 	var out []depLine
 	for _, ln := range lines {
 		// 提示行（pid为0）的处理：在有搜索词时，为了减少干扰，通常会隐藏它们。
@@ -230,8 +226,13 @@ func applyDepFilters(m model, lines []depLine) []depLine {
 			continue
 		}
 
+		// 应用结构化条件。
+		if !query.Match(it, now) {
+			continue
+		}
+
 		// 应用文本过滤器。
-		if hasTerm {
+		if term != "" {
 			// 搜索词不区分大小写地匹配行文本。
 			match := strings.Contains(strings.ToLower(ln.text), strings.ToLower(term))
 			if !match {
@@ -255,11 +256,7 @@ func applyDepFilters(m model, lines []depLine) []depLine {
 // 从父进程PID（PPID）到其直接子进程列表的映射（map）。
 // 这个映射是构建依赖树的基础，因为它使得查找任何进程的子节点变得非常高效。
 func (m model) buildChildrenMap() map[int32][]*process.Item {
-	mp := make(map[int32][]*process.Item)
-	for _, it := range m.processes {
-		mp[it.PPid] = append(mp[it.PPid], it)
-	}
-	return mp
+	return process.ChildrenMap(m.processes)
 }
 
 // findProcess 是一个优化的辅助函数。
@@ -275,26 +272,13 @@ func (m model) findProcess(pid int32) *process.Item {
 }
 
 // buildAncestorLines 函数用于构建并格式化当前根进程的祖先链。
-// 它从根进程开始，通过 `findProcess` 不断向上追溯其父进程，直到达到系统根（PPID为0）或达到 `ancestorChainLimit` 限制。
+// 它从根进程开始，通过 `process.Ancestors` 不断向上追溯其父进程，直到达到系统根（PPID为0）或达到 `ancestorChainLimit` 限制。
 func (m model) buildAncestorLines(root *process.Item) []string {
 	if root == nil {
 		return nil
 	}
-	// 1. 向上追溯，收集祖先进程。
-	chain := make([]*process.Item, 0, ancestorChainLimit)
-	cur := root
-	for i := 0; i < ancestorChainLimit; i++ {
-		if cur.PPid == 0 {
-			break // 到达进程树的顶端。
-		}
-		p := m.findProcess(cur.PPid)
-		if p == nil {
-			break // 在当前进程列表中找不到父进程。
-		}
-		chain = append(chain, p)
-		cur = p
-	}
-
+	// 1. 向上追溯，收集祖先进程（由近及远）。
+	chain := process.Ancestors(m.processes, root, ancestorChainLimit)
 	if len(chain) == 0 {
 		return nil
 	}
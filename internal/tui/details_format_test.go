@@ -7,6 +7,8 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
+	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/why"
 )
 
 var ansiEscapeRE = regexp.MustCompile(`\x1b\[[0-9;]*m`)
@@ -16,27 +18,22 @@ func stripANSI(s string) string {
 }
 
 func TestComputeDetailLabelWidthMinAndCap(t *testing.T) {
-	lines := []string{
-		"  PID:\t123",
-		"  Name:\tfoo",
-	}
-	if w := computeDetailLabelWidth(lines, 80); w < 12 {
+	labels := []string{"PID", "Name"}
+	if w := computeDetailLabelWidth(labels, 80); w < 12 {
 		t.Fatalf("label width should be at least 12, got %d", w)
 	}
 
-	longLabelLines := []string{
-		"  ExtremelyLongLabelNameThatShouldBeCapped:\tvalue",
-	}
-	if w := computeDetailLabelWidth(longLabelLines, 80); w > 24 {
+	longLabels := []string{"ExtremelyLongLabelNameThatShouldBeCapped"}
+	if w := computeDetailLabelWidth(longLabels, 80); w > 24 {
 		t.Fatalf("label width should be capped at 24, got %d", w)
 	}
 }
 
 func TestFormatProcessDetailsWrapValueAndIndent(t *testing.T) {
-	details := strings.Join([]string{
-		"  PID:\t123",
-		"  Command:\tthis is a very long command line with many words to wrap nicely",
-	}, "\n") + "\n"
+	details := &process.Details{
+		Pid:     123,
+		Cmdline: "this is a very long command line with many words to wrap nicely",
+	}
 
 	out := stripANSI(formatProcessDetails(details, 40))
 	lines := strings.Split(out, "\n")
@@ -74,15 +71,17 @@ func TestFormatProcessDetailsWrapValueAndIndent(t *testing.T) {
 }
 
 func TestFormatProcessDetailsWhySegments(t *testing.T) {
-	details := strings.Join([]string{
-		"  PID:\t123",
-		"",
-		"  ─────────────────────────────────────",
-		"  Why It Exists:",
-		"    systemd (pid 1) → init-systemd (pid 2) → SessionLeader (pid 1995)",
-		"",
-		"  Source:\tsystemd",
-	}, "\n") + "\n"
+	details := &process.Details{
+		Pid: 123,
+		Why: &why.AnalysisResult{
+			Ancestry: []why.ProcessInfo{
+				{PID: 1, Command: "systemd"},
+				{PID: 2, Command: "init-systemd"},
+				{PID: 1995, Command: "SessionLeader"},
+			},
+			Source: why.Source{Type: why.SourceSystemd},
+		},
+	}
 
 	out := stripANSI(formatProcessDetails(details, 60))
 	lines := strings.Split(out, "\n")
@@ -134,21 +133,14 @@ func TestFormatProcessDetailsWarningsSectionHighlights(t *testing.T) {
 	// Force a color profile so lipgloss emits ANSI sequences in tests.
 	lipgloss.SetColorProfile(termenv.TrueColor)
 
-	details := strings.Join([]string{
-		"  PID:\t123",
-		"",
-		"  ─────────────────────────────────────",
-		"  Why It Exists:",
-		"    systemd (pid 1) → node (pid 123)",
-		"",
-		"  Source:\tsystemd",
-		"",
-		"  Warnings:",
-		"  ⚠ Process is running as root",
-		"  ⚠ Process is using high memory (>1GB RSS)",
-		"",
-		"  ─────────────────────────────────────",
-	}, "\n") + "\n"
+	details := &process.Details{
+		Pid: 123,
+		Why: &why.AnalysisResult{
+			Ancestry: []why.ProcessInfo{{PID: 1, Command: "systemd"}, {PID: 123, Command: "node"}},
+			Source:   why.Source{Type: why.SourceSystemd},
+		},
+		Warnings: []string{"Process is running as root", "Process is using high memory (>1GB RSS)"},
+	}
 
 	out := formatProcessDetails(details, 60)
 
@@ -167,3 +159,16 @@ func TestFormatProcessDetailsWarningsSectionHighlights(t *testing.T) {
 		t.Fatalf("expected at least one warning line to include ANSI styling, got:\n%s", out)
 	}
 }
+
+func TestFormatProcessDetailsEnvLinesStayWhole(t *testing.T) {
+	details := &process.Details{
+		Pid: 123,
+		Why: &why.AnalysisResult{Source: why.Source{Type: why.SourceShell}},
+		Env: &process.DetailsEnv{Vars: []string{"PATH=/usr/bin:/bin"}},
+	}
+
+	out := stripANSI(formatProcessDetails(details, 80))
+	if !strings.Contains(out, "PATH=/usr/bin:/bin") {
+		t.Fatalf("expected env entry to be rendered unsplit, got:\n%s", out)
+	}
+}
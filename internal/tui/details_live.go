@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// detailsLiveInterval 是详情视图实时模式（`L`）的采样间隔。
+const detailsLiveInterval = time.Second
+
+// detailsHistoryLen 是保留并绘制为 sparkline 的最近采样数。
+const detailsHistoryLen = 60
+
+// sparkBlocks 是 sparkline 使用的 8 级字符，从低到高。
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// detailsMetricsMsg 携带一次实时采样的结果。requestID 与详情请求相同，liveID 标识发起它的实时会话，
+// 二者任一不匹配即视为过期响应并丢弃。
+type detailsMetricsMsg struct {
+	requestID int64
+	liveID    int
+	metrics   process.Metrics
+	err       error
+}
+
+// detailsLiveTickMsg 在采样间隔到达时触发下一次采样，过期判定同 detailsMetricsMsg。
+type detailsLiveTickMsg struct {
+	requestID int64
+	liveID    int
+}
+
+// sampleDetailsMetrics 是一个命令工厂函数，用 sampler 在后台对 pid 采样一次。startTime 非零时，
+// 若该 PID 已属于另一个进程则返回错误，实时模式随之停止，不会把新进程的数据接在旧曲线后面。
+func sampleDetailsMetrics(sampler *process.MetricsSampler, pid int, startTime time.Time, requestID int64, liveID int) tea.Cmd {
+	return func() tea.Msg {
+		metrics, err := sampler.Sample(pid, startTime)
+		return detailsMetricsMsg{requestID: requestID, liveID: liveID, metrics: metrics, err: err}
+	}
+}
+
+// toggleDetailsLive 开启或关闭实时模式。开启时立即采样，之后每次采样完成再计时，
+// 因此任何时候最多只有一个采样在途。
+func (m model) toggleDetailsLive() (model, tea.Cmd) {
+	m.detailsLive = !m.detailsLive
+	m.detailsLiveID++ // 使上一个会话的计时器和在途采样失效。
+	m.detailsLiveErr = ""
+	if !m.detailsLive {
+		return m, nil
+	}
+	// 每个实时会话使用独立的采样器，CPU 增量只在相邻两次实时采样之间计算，不受列表刷新影响。
+	m.detailsSampler = process.NewMetricsSampler()
+	return m, sampleDetailsMetrics(m.detailsSampler, int(m.detailsPID), m.detailsStartTime, m.detailsRequestID, m.detailsLiveID)
+}
+
+// resetDetailsLive 关闭实时模式并清空历史，用于关闭详情或切换到另一个进程。
+func (m model) resetDetailsLive() model {
+	m.detailsLive = false
+	m.detailsLiveID++
+	m.detailsLiveErr = ""
+	m.detailsHistory = nil
+	return m
+}
+
+func (m model) liveMsgCurrent(requestID int64, liveID int) bool {
+	return m.showDetails && m.detailsLive && requestID == m.detailsRequestID && liveID == m.detailsLiveID
+}
+
+func (m model) updateDetailsMetrics(msg detailsMetricsMsg) (tea.Model, tea.Cmd) {
+	if !m.liveMsgCurrent(msg.requestID, msg.liveID) {
+		return m, nil
+	}
+	if msg.err != nil {
+		// 进程已退出或无法读取：停止实时模式，保留已有曲线供查看。
+		m.detailsLive = false
+		m.detailsLiveErr = msg.err.Error()
+		return m, nil
+	}
+	m.detailsHistory = append(m.detailsHistory, msg.metrics)
+	if len(m.detailsHistory) > detailsHistoryLen {
+		m.detailsHistory = m.detailsHistory[len(m.detailsHistory)-detailsHistoryLen:]
+	}
+	requestID, liveID := m.detailsRequestID, m.detailsLiveID
+	return m, tea.Tick(detailsLiveInterval, func(time.Time) tea.Msg {
+		return detailsLiveTickMsg{requestID: requestID, liveID: liveID}
+	})
+}
+
+func (m model) updateDetailsLiveTick(msg detailsLiveTickMsg) (tea.Model, tea.Cmd) {
+	if !m.liveMsgCurrent(msg.requestID, msg.liveID) {
+		return m, nil
+	}
+	return m, sampleDetailsMetrics(m.detailsSampler, int(m.detailsPID), m.detailsStartTime, m.detailsRequestID, m.detailsLiveID)
+}
+
+// renderDetailsMetrics 渲染详情视图头部的一行实时指标：每项为标签、最近历史的 sparkline 与当前值。
+// 尚无采样时显示开启提示。结果截断到 width，保证始终只占一行（viewport 高度按一行计算）。
+func (m model) renderDetailsMetrics(width int) string {
+	if len(m.detailsHistory) == 0 {
+		if m.detailsLiveErr != "" {
+			return warningStyle.Render(" live stopped: ") + faintStyle.Render(m.detailsLiveErr)
+		}
+		if m.detailsLive {
+			return faintStyle.Render(" sampling…")
+		}
+		return faintStyle.Render(" L: live CPU / RSS / FDs / threads / IO sparklines")
+	}
+
+	h := m.detailsHistory
+	last := h[len(h)-1]
+	series := func(f func(process.Metrics) float64) []float64 {
+		out := make([]float64, len(h))
+		for i, s := range h {
+			out[i] = f(s)
+		}
+		return out
+	}
+	readRate, writeRate := ioRates(h)
+	ioSeries := make([]float64, len(readRate))
+	for i := range readRate {
+		ioSeries[i] = readRate[i] + writeRate[i]
+	}
+
+	type metric struct {
+		label  string
+		values []float64
+		value  string
+	}
+	metrics := []metric{
+		{"CPU", series(func(s process.Metrics) float64 { return s.CPUPercent }), fmt.Sprintf("%.1f%%", last.CPUPercent)},
+		{"RSS", series(func(s process.Metrics) float64 { return float64(s.RSS) }), compactBytes(last.RSS)},
+		{"FDs", series(func(s process.Metrics) float64 { return float64(s.FDs) }), fmt.Sprintf("%d", last.FDs)},
+		{"THR", series(func(s process.Metrics) float64 { return float64(s.Threads) }), fmt.Sprintf("%d", last.Threads)},
+	}
+	if last.HasIO {
+		io := "r - w -"
+		if n := len(readRate); n > 0 {
+			io = fmt.Sprintf("r %s/s w %s/s", compactBytes(uint64(readRate[n-1])), compactBytes(uint64(writeRate[n-1])))
+		}
+		metrics = append(metrics, metric{"IO", ioSeries, io})
+	}
+
+	// 先排好固定文本，再把剩余宽度平均分给各条 sparkline。
+	fixed := 1
+	for _, mt := range metrics {
+		fixed += lipgloss.Width(mt.label) + lipgloss.Width(mt.value) + 4
+	}
+	sparkWidth := (width - fixed) / len(metrics)
+	if sparkWidth > 20 {
+		sparkWidth = 20
+	}
+	if sparkWidth < 4 {
+		sparkWidth = 4
+	}
+
+	parts := make([]string, 0, len(metrics))
+	for _, mt := range metrics {
+		parts = append(parts, detailTableHeaderStyle.Render(mt.label)+" "+usageStyle.Render(sparkline(mt.values, sparkWidth))+" "+detailValueStyle.Render(mt.value))
+	}
+	line := " " + strings.Join(parts, "  ")
+	if !m.detailsLive {
+		line += faintStyle.Render("  (paused)")
+	}
+	if width > 0 {
+		line = lipgloss.NewStyle().MaxWidth(width).Render(line)
+	}
+	return line
+}
+
+// ioRates 把累计 IO 计数换算为相邻采样之间的读写速率（字节/秒）；缺少 IO 的采样记为 0。
+func ioRates(h []process.Metrics) (read, write []float64) {
+	for i := 1; i < len(h); i++ {
+		prev, cur := h[i-1], h[i]
+		dt := cur.At.Sub(prev.At).Seconds()
+		var r, w float64
+		if dt > 0 && prev.HasIO && cur.HasIO && cur.ReadBytes >= prev.ReadBytes && cur.WriteBytes >= prev.WriteBytes {
+			r = float64(cur.ReadBytes-prev.ReadBytes) / dt
+			w = float64(cur.WriteBytes-prev.WriteBytes) / dt
+		}
+		read, write = append(read, r), append(write, w)
+	}
+	return read, write
+}
+
+// sparkline 用最近 width 个值绘制一条 sparkline，按这些值的最小/最大值缩放，
+// 因此缓慢增长（例如内存泄漏）也清晰可见；全部相等时画成一条底线。
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return strings.Repeat(" ", width)
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
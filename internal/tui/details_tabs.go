@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// detailsTab 标识详情视图中的一个标签页。
+type detailsTab int
+
+const (
+	tabOverview detailsTab = iota
+	tabNetwork
+	tabFiles
+	tabThreads
+	tabLimits
+	tabEnv
+	tabCgroup
+	detailsTabCount
+)
+
+var detailsTabNames = [detailsTabCount]string{"Overview", "Network", "Files", "Threads", "Limits", "Env", "Cgroup"}
+
+func (t detailsTab) String() string {
+	return detailsTabNames[t]
+}
+
+// next 返回下一个（delta=1）或上一个（delta=-1）标签页，首尾循环。
+func (t detailsTab) next(delta int) detailsTab {
+	return detailsTab((int(t) + delta + int(detailsTabCount)) % int(detailsTabCount))
+}
+
+// detailsTabMsg 携带一个按需加载的标签页数据。requestID 与 processDetailsMsg 相同，
+// 用于丢弃切换进程或重新加载之前发出的过期响应。
+type detailsTabMsg struct {
+	requestID int64
+	tab       detailsTab
+	data      any
+	err       error
+}
+
+// detailsTabResult 是一个已加载标签页的数据或错误。
+type detailsTabResult struct {
+	data any
+	err  error
+}
+
+// loadDetailsTab 是一个命令工厂函数，在后台加载除 Overview 以外的某个标签页的完整数据。
+func loadDetailsTab(tab detailsTab, pid int, requestID int64, revealSecrets bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := detailsTabMsg{requestID: requestID, tab: tab}
+		switch tab {
+		case tabNetwork:
+			msg.data, msg.err = process.GetSockets(pid)
+		case tabFiles:
+			msg.data, msg.err = process.GetOpenFiles(pid)
+		case tabThreads:
+			msg.data, msg.err = process.GetThreads(pid)
+		case tabLimits:
+			msg.data, msg.err = process.GetLimits(pid)
+		case tabEnv:
+			msg.data, msg.err = process.GetEnv(pid, revealSecrets)
+		case tabCgroup:
+			msg.data, msg.err = process.GetCgroups(pid)
+		}
+		return msg
+	}
+}
+
+// selectDetailsTab 切换到 tab；若该标签页尚未加载，则发起加载。
+func (m model) selectDetailsTab(tab detailsTab) (model, tea.Cmd) {
+	m.detailsTab = tab
+	m.detailsViewport.GotoTop()
+	if tab == tabOverview {
+		m.setDetailsContent()
+		return m, nil
+	}
+	if _, ok := m.detailsTabs[tab]; ok {
+		m.setDetailsContent()
+		return m, nil
+	}
+	m.detailsViewport.SetContent("Loading...")
+	return m, loadDetailsTab(tab, int(m.detailsPID), m.detailsRequestID, m.detailsRevealSecrets)
+}
+
+func (m model) updateDetailsTab(msg detailsTabMsg) (tea.Model, tea.Cmd) {
+	if msg.requestID != m.detailsRequestID || !m.showDetails {
+		return m, nil
+	}
+	m.detailsTabs[msg.tab] = detailsTabResult{data: msg.data, err: msg.err}
+	if msg.tab == m.detailsTab {
+		m.setDetailsContent()
+	}
+	return m, nil
+}
+
+// setDetailsContent 按当前标签页重新渲染 viewport 内容。内容较短时收缩 viewport，
+// 但不超过窗口允许的高度。
+func (m *model) setDetailsContent() {
+	vpHFrame, _ := m.detailsViewport.Style.GetFrameSize()
+	contentWidth := m.detailsViewport.Width - vpHFrame
+
+	var content string
+	if m.detailsTab == tabOverview {
+		if m.processDetails == nil {
+			content = "Loading..."
+		} else {
+			content = formatProcessDetails(m.processDetails, contentWidth)
+		}
+	} else if res, ok := m.detailsTabs[m.detailsTab]; ok {
+		content = renderDetailsTab(res, contentWidth)
+	} else {
+		content = "Loading..."
+	}
+
+	contentHeight := len(strings.Split(content, "\n"))
+	maxHeight := m.detailsMaxHeight
+	if maxHeight <= 0 {
+		maxHeight = contentHeight
+	}
+	m.detailsViewport.Height = minInt(contentHeight, maxHeight)
+	m.detailsViewport.SetContent(content)
+}
+
+// renderDetailsTabBar 渲染标签栏，当前标签页高亮，并附上数字快捷键。
+func (m model) renderDetailsTabBar() string {
+	parts := make([]string, 0, detailsTabCount)
+	for t := detailsTab(0); t < detailsTabCount; t++ {
+		label := fmt.Sprintf(" %d %s ", int(t)+1, t)
+		if t == m.detailsTab {
+			parts = append(parts, selectedStyle.Render(label))
+		} else {
+			parts = append(parts, faintStyle.Render(label))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderDetailsTab 渲染 Overview 以外的标签页。
+func renderDetailsTab(res detailsTabResult, width int) string {
+	if res.err != nil {
+		return warningStyle.Render("unavailable: ") + detailValueStyle.Render(res.err.Error())
+	}
+	switch data := res.data.(type) {
+	case []process.Socket:
+		rows := make([][]string, 0, len(data))
+		for _, s := range data {
+			rows = append(rows, []string{s.Proto, fmt.Sprintf("%d", s.FD), s.Local, s.Remote, s.State})
+		}
+		return renderDetailsTable(fmt.Sprintf("%d sockets", len(data)), []string{"PROTO", "FD", "LOCAL", "REMOTE", "STATE"}, rows, width)
+	case []process.OpenFile:
+		rows := make([][]string, 0, len(data))
+		for _, f := range data {
+			rows = append(rows, []string{fmt.Sprintf("%d", f.FD), f.Target})
+		}
+		return renderDetailsTable(fmt.Sprintf("%d open file descriptors", len(data)), []string{"FD", "TARGET"}, rows, width)
+	case []process.Thread:
+		rows := make([][]string, 0, len(data))
+		for _, th := range data {
+			rows = append(rows, []string{fmt.Sprintf("%d", th.TID), th.State, fmt.Sprintf("%.2fs", th.CPUTime), th.Name})
+		}
+		return renderDetailsTable(fmt.Sprintf("%d threads", len(data)), []string{"TID", "STATE", "CPU TIME", "NAME"}, rows, width)
+	case []process.Limit:
+		rows := make([][]string, 0, len(data))
+		for _, l := range data {
+			rows = append(rows, []string{l.Name, l.Soft, l.Hard, l.Units})
+		}
+		return renderDetailsTable(fmt.Sprintf("%d limits", len(data)), []string{"LIMIT", "SOFT", "HARD", "UNITS"}, rows, width)
+	case []process.Cgroup:
+		rows := make([][]string, 0, len(data))
+		for _, cg := range data {
+			rows = append(rows, []string{cg.Hierarchy, strings.Join(cg.Controllers, ","), cg.Path})
+		}
+		return renderDetailsTable(fmt.Sprintf("%d cgroups", len(data)), []string{"ID", "CONTROLLERS", "PATH"}, rows, width)
+	case *process.DetailsEnv:
+		lines := []string{faintStyle.Render(fmt.Sprintf("%d variables", len(data.Vars)))}
+		for _, line := range data.AllLines() {
+			for _, wl := range wrapPlainText(line, width) {
+				lines = append(lines, detailValueStyle.Render(wl))
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	return faintStyle.Render("(no data)")
+}
+
+// renderDetailsTable 渲染一个简单的列对齐表格：除最后一列外按内容宽度对齐，
+// 最后一列占用剩余宽度，过长时换行并缩进到该列起始处，保证路径等内容完整可见。
+func renderDetailsTable(summary string, header []string, rows [][]string, width int) string {
+	if len(rows) == 0 {
+		return faintStyle.Render(summary)
+	}
+
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = lipgloss.Width(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row[:len(row)-1] {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+
+	lastColumn := 0
+	for _, w := range widths[:len(widths)-1] {
+		lastColumn += w + 2
+	}
+
+	format := func(cells []string, style lipgloss.Style) []string {
+		var b strings.Builder
+		for i, cell := range cells[:len(cells)-1] {
+			b.WriteString(cell)
+			b.WriteString(strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+2))
+		}
+		last := []string{cells[len(cells)-1]}
+		if width-lastColumn >= 10 {
+			last = wrapPlainText(last[0], width-lastColumn)
+		}
+		out := []string{style.Render(strings.TrimRight(b.String()+last[0], " "))}
+		for _, cont := range last[1:] {
+			out = append(out, strings.Repeat(" ", lastColumn)+style.Render(cont))
+		}
+		return out
+	}
+
+	lines := append([]string{faintStyle.Render(summary)}, format(header, detailTableHeaderStyle)...)
+	for _, row := range rows {
+		lines = append(lines, format(row, detailValueStyle)...)
+	}
+	return strings.Join(lines, "\n")
+}
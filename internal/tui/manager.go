@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// manager.go 实现“通过管理器停止”（`M`）。
+//
+// 由 systemd、pm2、supervisord 或 docker 管理的进程被直接 kill 后通常会被立即拉起。`M` 先在
+// 后台根据 why 分析出的来源推导出对应的停止命令（如 `systemctl stop nginx.service`），再在确认
+// 对话框中原样预览，确认后执行该命令而不是发送信号。
+
+// managerLookupMsg 携带 `M` 推导停止命令的结果。id 与 `model.managerLookupID` 不一致的结果
+// 属于已被新的 `M` 取代的查找，直接丢弃。
+type managerLookupMsg struct {
+	id        int
+	depMode   bool // 发起查找时是否处于 T 模式。
+	pid       int32
+	startTime time.Time
+	name      string
+	cmd       *process.ManagerCommand
+	err       error
+}
+
+// managerStoppedMsg 携带停止命令的执行结果。
+type managerStoppedMsg struct {
+	pid     int32
+	cmd     process.ManagerCommand
+	err     error
+	started time.Time
+}
+
+// lookupManagerStop 为 it 打开“通过管理器停止”：分析来源可能需要一两秒，因此在后台进行，
+// 期间在提示区显示进度。
+func (m model) lookupManagerStop(it *process.Item) (model, tea.Cmd) {
+	if it == nil {
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("M: looking up what manages %s (%d)…", it.Executable, it.Pid)
+	m.managerLookupID++
+	id, depMode := m.managerLookupID, m.dep.mode
+	target := *it
+	return m, func() tea.Msg {
+		cmd, err := process.ManagerStopCommand(&target)
+		return managerLookupMsg{id: id, depMode: depMode, pid: target.Pid, startTime: target.StartTime, name: target.Executable, cmd: cmd, err: err}
+	}
+}
+
+// managerLookupCurrent 报告查找结果是否仍对应用户当前的上下文：它是最近一次查找，用户仍停留在
+// 同一视图、光标仍在同一进程上，且没有打开任何对话框、详情或帮助。
+func (m model) managerLookupCurrent(msg managerLookupMsg) bool {
+	if msg.id != m.managerLookupID || msg.depMode != m.dep.mode {
+		return false
+	}
+	if m.confirm != nil || m.signalMenu != nil || m.showDetails || m.helpOpen || m.err != nil {
+		return false
+	}
+	var it *process.Item
+	if m.dep.mode {
+		if ln, ok := m.depLineAtCursor(); ok {
+			it = m.findProcess(ln.pid)
+		}
+	} else if p, ok := m.selectedProcess(); ok {
+		it = p
+	}
+	return it != nil && it.Pid == msg.pid && it.StartTime.Equal(msg.startTime)
+}
+
+// updateManagerLookup 在推导成功时弹出预览命令的确认对话框。查找期间用户移到了其他行、
+// 打开了详情或切换了视图时，结果已不再对应眼前的进程，直接丢弃。
+func (m model) updateManagerLookup(msg managerLookupMsg) (tea.Model, tea.Cmd) {
+	if !m.managerLookupCurrent(msg) {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+		return m, nil
+	}
+	m.notice = ""
+	m.confirm = &confirmPrompt{pid: msg.pid, startTime: msg.startTime, name: msg.name, op: "stop via " + msg.cmd.Manager, status: process.Killed, manager: msg.cmd}
+	return m, nil
+}
+
+// runManagerStop 是执行停止命令的命令工厂。
+func runManagerStop(pid int32, cmd process.ManagerCommand) tea.Cmd {
+	return func() tea.Msg {
+		started := time.Now()
+		err := process.RunManagerCommand(context.Background(), cmd)
+		return managerStoppedMsg{pid: pid, cmd: cmd, err: err, started: started}
+	}
+}
+
+// updateManagerStopped 成功时把进程标记为已杀死、报告执行的命令并开始重生观察；失败时显示错误面板。
+func (m model) updateManagerStopped(msg managerStoppedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = ""
+		m.err = msg.err
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("stopped via `%s`", msg.cmd)
+	var stopped []*process.Item
+	if it := m.findProcess(msg.pid); it != nil {
+		it.Status = process.Killed
+		stopped = append(stopped, it)
+	}
+	return m.refilterKeepingSelection().trackDeparted(stopped, msg.started)
+}
@@ -2,18 +2,16 @@ package tui
 
 import (
 	"fmt"
-	"os"
-	"sort"
-	"strconv"
-	"strings"
+	"syscall"
+	"time"
 
 	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/search"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/sahilm/fuzzy"
 )
 
 // --- Bubble Tea 消息定义 ---
@@ -27,13 +25,31 @@ import (
 type processesLoadedMsg struct {
 	processes []*process.Item // 成功获取的进程列表。
 	warnings  []error         // 获取过程中遇到的非致命错误。
+	err       error           // 整次扫描失败时的错误；此时 processes 为空。
 }
 
-// processDetailsMsg 是一条消息，用于携带从 `process.GetProcessDetails` 获取到的单个进程的详细信息。
+// autoRefreshTickMsg 由自动刷新的 `tea.Tick` 产生。id 与 `model.tickID` 不一致的消息
+// 属于已被取代的计时器（例如切换了自动刷新，或手动刷新后重新计时），直接忽略。
+type autoRefreshTickMsg struct {
+	id int
+}
+
+// defaultKillGrace 是 kill 在 SIGTERM 之后、升级为 SIGKILL 之前的默认宽限期。
+const defaultKillGrace = 3 * time.Second
+
+// defaultRefreshInterval 是按 `R` 开启自动刷新、但未通过 `--refresh` 指定间隔时使用的刷新间隔。
+const defaultRefreshInterval = 2 * time.Second
+
+// processDetailsMsg 是一条消息，用于携带从 `process.GetDetails` 获取到的单个进程的详细信息。
 // requestID is used to ignore out-of-order responses when details are refreshed quickly.
 type processDetailsMsg struct {
 	requestID int64
-	details   string
+	details   *process.Details
+}
+
+// portFreedMsg 携带一次“释放端口”操作（`F`）的结果。
+type portFreedMsg struct {
+	result *process.FreePortResult
 }
 
 // errMsg 是一条用于传递错误的专用消息。当任何命令（`tea.Cmd`）的执行过程中发生错误时，
@@ -56,7 +72,8 @@ type signalOKMsg struct {
 type model struct {
 	// --- 核心数据 ---
 	// processes 存储从系统中获取的原始、完整的进程列表。它作为一份不可变的缓存，
-	// 所有的过滤和操作都基于这份数据，直到下一次刷新（`getProcesses` 命令）。
+	// 所有的过滤和操作都基于这份数据，直到下一次刷新（`refreshProcesses` 命令）。
+	// 刷新结果通过 `process.Merge` 合并进来，条目指针和 Killed/Paused 标记会被保留。
 	processes []*process.Item
 	// filtered 存储根据用户输入（搜索词）和视图模式（如 portsOnly）过滤后的进程列表。
 	// 这是在主列表视图中实际向用户展示的数据。
@@ -70,6 +87,17 @@ type model struct {
 	textInput textinput.Model
 	// warnings 存储从 `processesLoadedMsg` 中获取的非致命错误列表，用于在UI上提示用户。
 	warnings []error
+	// loaded 表示是否已收到过一次实时进程列表；在此之前 `processes` 中是缓存数据。
+	loaded bool
+	// refreshing 表示有一次进程扫描正在进行。同一时间最多只运行一次扫描，
+	// 期间请求的刷新通过 refreshQueued 记下，在当前扫描完成后再执行。
+	refreshing    bool
+	refreshQueued bool
+	// autoRefresh 开启时，每次扫描完成后等待 refreshInterval 再自动刷新（`R` 切换）。
+	autoRefresh     bool
+	refreshInterval time.Duration
+	// tickID 标识当前有效的自动刷新计时器，每次重新计时都会递增。
+	tickID int
 
 	// --- 视图模式与覆盖层 ---
 	// err 用于存储在应用运行过程中可能发生的、需要向用户展示的错误。
@@ -77,27 +105,75 @@ type model struct {
 	err error
 	// showDetails 是一个布尔标志，用于控制是显示主进程列表还是显示单个进程的详细信息视图。
 	showDetails bool
-	// processDetails 存储从 `GetProcessDetails` 获取到的、准备在详情视图中显示的字符串。
-	processDetails string
+	// processDetails 存储从 `GetDetails` 获取到的、准备在详情视图中显示的进程详情。
+	processDetails *process.Details
 	// detailsPID is the PID currently shown in the details view.
 	detailsPID int32
+	// detailsStartTime is the start time of that process, so live sampling stops if the PID is reused.
+	detailsStartTime time.Time
 	// detailsRequestID increments on each details refresh to ignore out-of-order responses.
 	detailsRequestID int64
 	// detailsVerbose toggles deeper (bounded) data collection in the details view.
 	detailsVerbose bool
-	// detailsShowEnv toggles environment variable display in the details view.
-	detailsShowEnv bool
 	// detailsRevealSecrets controls whether env values are shown without redaction.
 	detailsRevealSecrets bool
+	// detailsTab 是详情视图当前显示的标签页。
+	detailsTab detailsTab
+	// detailsTabs 缓存 Overview 以外已加载的标签页；切换到未加载的标签页时才发起加载。
+	detailsTabs map[detailsTab]detailsTabResult
+	// detailsMaxHeight 是当前窗口下详情 viewport 的最大高度，内容较短时 viewport 会收缩。
+	detailsMaxHeight int
+	// detailsLive 表示详情视图的实时模式（`L`）是否开启。
+	detailsLive bool
+	// detailsLiveID 在每次开关实时模式或切换进程时递增，使旧会话的计时器与在途采样失效。
+	detailsLiveID int
+	// detailsSampler 是当前实时会话的采样器，与列表扫描的 CPU 采样相互独立。
+	detailsSampler *process.MetricsSampler
+	// detailsLiveErr 记录导致实时模式停止的错误（通常是进程已退出）。
+	detailsLiveErr string
+	// detailsHistory 是实时模式最近的采样，用于绘制 sparkline。
+	detailsHistory []process.Metrics
 	// detailsViewport 是一个用于显示长文本内容的滚动视图组件。
 	detailsViewport viewport.Model
 	// portsOnly 是一个布尔标志，当为 `true` 时，主列表只显示那些正在监听端口的进程。
 	portsOnly bool
+	// userFilter 非空时，主列表只显示该用户拥有的进程（来自 `--user`）。
+	userFilter string
+	// sortKey 和 sortDesc 是主列表的排序方式（`s` 切换列，`S` 反转方向），在刷新之间保持不变。
+	sortKey  search.SortKey
+	sortDesc bool
+	// showCmdline 为 `true` 时在进程名后显示命令行参数（`c` 切换）。
+	showCmdline bool
+	// absoluteStart 为 `true` 时启动时间列显示绝对时间，否则显示已运行时长（`a` 切换）。
+	absoluteStart bool
+	// verboseByDefault 决定新打开的详情视图是否默认启用 verbose 模式（来自 `--verbose-details`）。
+	verboseByDefault bool
 	// confirm 指向一个 `confirmPrompt` 结构体，当需要用户确认一个危险操作（如杀死进程）时，
 	// 这个指针会被设置。当它不为 `nil` 时，`View` 函数会渲染一个确认对话框覆盖层。
 	confirm *confirmPrompt
+	// managerLookupID 在每次 `M` 查找时递增，用于丢弃被取代的查找结果。
+	managerLookupID int
+	// confirmScroll 是确认对话框目标列表的滚动偏移；confirmListHeight 是按窗口高度计算的可见行数。
+	confirmScroll     int
+	confirmListHeight int
+	// killOptions 是 kill（enter / x / 批量 kill）使用的 TERM→KILL 策略：宽限期与是否升级为 SIGKILL。
+	killOptions process.TerminateOptions
+	// signalMenu 非 nil 时显示信号选择菜单（`K`）。
+	signalMenu *signalMenu
+	// lastSignal 是上一次通过信号菜单发送的信号，再次打开菜单时光标停在它上面。
+	lastSignal syscall.Signal
 	// helpOpen 控制帮助菜单覆盖层是否显示。
 	helpOpen bool
+	// selected 记录多选（`space` / T 模式中的 `m`）选中的进程。非空时 kill、pause、resume
+	// 作用于整个选择，并通过一个确认对话框确认。
+	selected map[process.Key]bool
+	// departed 是观察期内被 kill 的进程，用于识别它们的重生；respawns 记录已识别的重生进程；
+	// respawnWatch 表示观察期的定期刷新正在进行。
+	departed     []process.Departed
+	respawns     map[process.Key]*respawnInfo
+	respawnWatch bool
+	// notice 是最近一次后台操作（如释放端口）的结果提示，显示在主列表底部，按任意键后清除。
+	notice string
 
 	// --- 依赖树 (T模式) 状态 ---
 	// dep 聚合了所有与依赖树视图相关的状态，例如当前根进程、节点的展开/折叠状态等。
@@ -105,13 +181,41 @@ type model struct {
 	dep depViewState
 }
 
-// InitialModel 创建并返回应用的初始状态模型。它在程序启动时被 `tea.NewProgram` 调用一次。
+// Options 描述了命令行传入的初始界面状态，使得 shell 别名可以直接打开到指定视图，
+// 例如 `gokill --ports-only 8080` 或 `gokill --tree 1234`。
+type Options struct {
+	// Filter 是初始搜索词。
+	Filter string
+	// PortsOnly 对应主列表的 `P` 模式。
+	PortsOnly bool
+	// User 只显示该用户拥有的进程。
+	User string
+	// TreePID 非零时，以该进程为根直接进入依赖树视图（T模式）。
+	TreePID int32
+	// DetailsPID 非零时，直接打开该进程的详情视图。
+	DetailsPID int32
+	// VerboseDetails 使详情视图默认启用 verbose 模式。
+	VerboseDetails bool
+	// RefreshInterval 大于零时启用自动刷新，并作为刷新间隔。
+	RefreshInterval time.Duration
+	// KillGrace 是 kill 发送 SIGTERM 后等待进程退出的时间，零值使用 defaultKillGrace。
+	KillGrace time.Duration
+	// NoEscalate 为 true 时，宽限期结束后不再发送 SIGKILL，只报告进程仍存活。
+	NoEscalate bool
+}
+
+// InitialModel 创建并返回应用的初始状态模型，只带一个初始搜索词。
 func InitialModel(filter string) model {
+	return InitialModelWithOptions(Options{Filter: filter})
+}
+
+// InitialModelWithOptions 根据命令行选项创建初始状态模型。它在程序启动时被 `tea.NewProgram` 调用一次。
+func InitialModelWithOptions(opts Options) model {
 	ti := textinput.New()
 	ti.Placeholder = "Search processes or ports"
 	ti.CharLimit = 156
 	ti.Width = 20
-	ti.SetValue(filter)
+	ti.SetValue(opts.Filter)
 
 	// 初始化详情视图的 viewport
 	vp := viewport.New(80, 20) // 初始大小，稍后会根据窗口大小调整
@@ -125,132 +229,60 @@ func InitialModel(filter string) model {
 
 	// 创建并初始化 model 结构体。
 	m := model{
-		textInput:       ti,     // 设置文本输入框组件。
-		processes:       cached, // 使用加载的缓存数据作为初始的完整进程列表。
-		detailsViewport: vp,     // 设置详情视图组件
+		textInput:         ti,     // 设置文本输入框组件。
+		processes:         cached, // 使用加载的缓存数据作为初始的完整进程列表。
+		detailsViewport:   vp,     // 设置详情视图组件
+		confirmListHeight: defaultConfirmListHeight,
+		portsOnly:         opts.PortsOnly,
+		userFilter:        opts.User,
+		verboseByDefault:  opts.VerboseDetails,
+		refreshing:        true, // Init 总会发起第一次扫描。
+		autoRefresh:       opts.RefreshInterval > 0,
+		refreshInterval:   opts.RefreshInterval,
+		killOptions:       process.TerminateOptions{Grace: opts.KillGrace, Escalate: !opts.NoEscalate},
 	}
-	// 根据初始的过滤条件（可能来自命令行参数）对缓存数据进行一次过滤。
-	m.filtered = m.filterProcesses(filter)
-	return m
-}
-
-// --- 模糊搜索逻辑 ---
-// 为了实现高效且用户友好的搜索功能，我们使用了 `sahilm/fuzzy` 库。
-// 这个库需要一个实现了 `fuzzy.Source` 接口的数据源。
-// `fuzzyProcessSource` 就是为我们的进程列表 `[]*process.Item` 实现这个接口的适配器。
-
-// fuzzyProcessSource 是一个适配器结构体，它包装了我们的进程列表 `[]*process.Item`，
-// 并实现了 `fuzzy.Source` 接口，使其能够被 `sahilm/fuzzy` 库使用。
-type fuzzyProcessSource struct {
-	processes []*process.Item
-}
-
-// String 是 `fuzzy.Source` 接口要求的方法。
-// 它返回在给定索引 `i` 处的项目的字符串表示形式，模糊搜索将在这个字符串上进行匹配。
-// 为了让用户可以同时通过进程名、PID、用户名、端口号或容器名进行搜索，我们将这几项信息拼接成一个单一的字符串。
-func (s fuzzyProcessSource) String(i int) string {
-	p := s.processes[i]
-	base := fmt.Sprintf("%s %s %d", p.Executable, p.User, p.Pid)
-	if p.ContainerName != "" {
-		base += " " + p.ContainerName
+	if m.killOptions.Grace <= 0 {
+		m.killOptions.Grace = defaultKillGrace
 	}
-	if ports := portsForSearch(p.Ports); ports != "" {
-		base += " " + ports
-	}
-	return base
-}
-
-// Len 是 `fuzzy.Source` 接口要求的另一个方法，返回数据源中的项目总数。
-func (s fuzzyProcessSource) Len() int {
-	return len(s.processes)
-}
-
-// filterProcesses 是模型的一个核心方法，它根据当前的过滤字符串和视图模式（如 `portsOnly`）
-// 来筛选 `m.processes` 中的进程，并返回一个新的 `filtered` 列表。
-func (m *model) filterProcesses(filter string) []*process.Item {
-	var result []*process.Item
-
-	// --- 第一步：无过滤字符串的特殊处理 ---
-	// 如果过滤字符串为空，逻辑很简单：遍历所有进程，应用 `portsOnly` 过滤器，并排除已杀死的进程。
-	if filter == "" {
-		for _, p := range m.processes {
-			if p.Status == process.Killed {
-				continue // 排除已标记为杀死的进程。
-			}
-			if m.portsOnly && len(p.Ports) == 0 {
-				continue // 在 "ports-only" 模式下，排除没有监听端口的进程。
-			}
-			result = append(result, p)
-		}
-		// 在 "ports-only" 模式下，对结果按第一个端口号进行稳定排序。
-		if m.portsOnly {
-			sort.SliceStable(result, func(i, j int) bool {
-				// 端口列表在采集时已保证有序，所以直接取第一个作为排序键。
-				return result[i].Ports[0] < result[j].Ports[0]
-			})
-		}
-		return result
-	}
-
-	// --- 第二步：使用模糊搜索 ---
-	// 如果存在过滤字符串，则使用 `sahilm/fuzzy` 库进行高效的模糊匹配。
-	// 1. 创建一个 `fuzzyProcessSource` 实例作为模糊搜索的数据源。
-	source := fuzzyProcessSource{processes: m.processes}
-	// 2. 调用 `fuzzy.FindFrom` 进行搜索，它会返回一个按匹配度排序的结果列表 `matches`。
-	matches := fuzzy.FindFrom(filter, source)
-
-	// 3. 根据匹配结果，从原始的 `m.processes` 列表中构建出过滤后的列表。
-	for _, match := range matches {
-		p := m.processes[match.Index]
-		if p.Status == process.Killed {
-			continue // 同样，排除已杀死的进程。
-		}
-		if m.portsOnly && len(p.Ports) == 0 {
-			continue // 在 "ports-only" 模式下应用过滤器。
-		}
-		result = append(result, p)
+	if m.refreshInterval <= 0 {
+		m.refreshInterval = defaultRefreshInterval
 	}
+	// 根据初始的过滤条件（可能来自命令行参数）对缓存数据进行一次过滤。
+	m.filtered = m.filterProcesses(opts.Filter)
 
-	// 在 "ports-only" 模式下，即使是模糊搜索的结果，也需要按端口号重新排序，
-	// 因为模糊搜索的排序是基于匹配度，而不是端口号。
-	if m.portsOnly {
-		sort.SliceStable(result, func(i, j int) bool {
-			return result[i].Ports[0] < result[j].Ports[0]
-		})
+	if opts.TreePID > 0 {
+		m = m.enterDepMode(opts.TreePID)
 	}
-
-	return result
-}
-
-// portsForSearch 是一个辅助函数，将端口号列表转换为一个用空格分隔的字符串，
-// 以便用于模糊搜索。
-func portsForSearch(ports []uint32) string {
-	if len(ports) == 0 {
-		return ""
+	if opts.DetailsPID > 0 {
+		// 详情内容的实际加载由 Init 发出的命令完成。
+		m, _ = m.openProcessDetails(opts.DetailsPID)
 	}
-	return strings.Join(portsToStrings(ports), " ")
+	return m
 }
 
-// portsToStrings 是一个辅助函数，将 `[]uint32` 类型的端口列表转换为 `[]string`。
-func portsToStrings(ports []uint32) []string {
-	parts := make([]string, len(ports))
-	for i, port := range ports {
-		parts[i] = strconv.FormatUint(uint64(port), 10)
-	}
-	return parts
+// filterProcesses 根据当前的过滤字符串和视图模式（如 `portsOnly`）筛选 `m.processes`，
+// 返回新的 `filtered` 列表。实际的模糊匹配逻辑位于 `search` 包中，与 CLI 共用，
+// 保证 `gokill list` 与界面中的搜索结果一致。
+func (m *model) filterProcesses(filter string) []*process.Item {
+	return search.Filter(m.processes, search.Options{
+		Query:      filter,
+		PortsOnly:  m.portsOnly,
+		User:       m.userFilter,
+		Sort:       m.sortKey,
+		Descending: m.sortDesc,
+	})
 }
 
 // Start 是 TUI 模块的公共入口点。
-// main.go 中的 main 函数会调用它来启动整个应用。
-func Start(filter string) {
+// cli 包在没有匹配到子命令时调用它来启动整个应用。
+func Start(opts Options) error {
 	// tea.NewProgram 创建一个新的 Bubble Tea 程序实例，
-	// 并使用我们定义的 InitialModel 来初始化其状态。
-	p := tea.NewProgram(InitialModel(filter))
+	// 并使用根据命令行选项构建的初始模型来初始化其状态。
+	p := tea.NewProgram(InitialModelWithOptions(opts))
 	// p.Run() 启动事件循环，开始渲染UI并处理消息。
 	// 这是一个阻塞调用，直到程序退出（例如用户按下 'q' 或 'ctrl+c'）。
 	if _, err := p.Run(); err != nil {
-		// 如果程序运行出错，则向标准错误输出打印错误信息并退出。
-		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error running program: %w", err)
 	}
+	return nil
 }
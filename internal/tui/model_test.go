@@ -1,11 +1,19 @@
 package tui
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/search"
+	"github.com/w31r4/gokill/internal/why"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestUpdate(t *testing.T) {
@@ -138,3 +146,692 @@ func TestFilterProcesses(t *testing.T) {
 		t.Errorf("expected to find process with pid 1 for port search, but got %#v", filtered)
 	}
 }
+
+func TestInitialModelWithOptionsSeedsViews(t *testing.T) {
+	m := InitialModelWithOptions(Options{
+		Filter:         "node",
+		PortsOnly:      true,
+		User:           "alice",
+		TreePID:        42,
+		DetailsPID:     43,
+		VerboseDetails: true,
+	})
+
+	if m.textInput.Value() != "node" || !m.portsOnly || m.userFilter != "alice" {
+		t.Fatalf("filters not seeded: value=%q portsOnly=%v user=%q", m.textInput.Value(), m.portsOnly, m.userFilter)
+	}
+	if !m.dep.mode || m.dep.rootPID != 42 {
+		t.Fatalf("expected T mode rooted at 42, got mode=%v root=%d", m.dep.mode, m.dep.rootPID)
+	}
+	if !m.showDetails || m.detailsPID != 43 || !m.detailsVerbose {
+		t.Fatalf("expected verbose details for 43, got show=%v pid=%d verbose=%v", m.showDetails, m.detailsPID, m.detailsVerbose)
+	}
+	if m.detailsRequestID == 0 {
+		t.Fatalf("expected a details request to be pending")
+	}
+}
+
+func TestUserFilterAppliesToList(t *testing.T) {
+	m := InitialModelWithOptions(Options{User: "bob"})
+	m.processes = []*process.Item{
+		process.NewItem(1, "foo", "alice"),
+		process.NewItem(2, "foo", "bob"),
+	}
+	m.filtered = m.filterProcesses("foo")
+	if len(m.filtered) != 1 || m.filtered[0].Pid != 2 {
+		t.Fatalf("expected only bob's process, got %#v", m.filtered)
+	}
+}
+
+func TestFreePortKeyAsksForConfirmation(t *testing.T) {
+	m := InitialModel("")
+	m.processes = []*process.Item{
+		process.NewItem(1, "idle", "test"),
+		process.NewItem(2, "web", "test", 8443, 8080),
+	}
+	m.filtered = m.processes
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m = newModel.(model)
+	if m.confirm != nil || m.notice == "" {
+		t.Fatalf("expected a notice for a process without ports, got confirm=%v notice=%q", m.confirm, m.notice)
+	}
+
+	m.cursor = 1
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m = newModel.(model)
+	if m.confirm == nil || m.confirm.port != 8080 {
+		t.Fatalf("expected confirmation to free port 8080, got %+v", m.confirm)
+	}
+	if len(m.confirm.holders) != 1 || m.confirm.holders[0].Pid != 2 || !strings.Contains(stripANSI(m.View()), "Listeners: web (2)") {
+		t.Fatalf("expected the confirmed listeners to be recorded and shown, got %+v", m.confirm.holders)
+	}
+	if m.notice != "" {
+		t.Fatalf("expected the previous notice to be cleared, got %q", m.notice)
+	}
+}
+
+func TestPortFreedMarksHoldersAndRefreshes(t *testing.T) {
+	m := InitialModel("")
+	m.refreshing = false // the initial scan has finished
+	web := process.NewItem(2, "web", "test", 8080)
+	m.processes = []*process.Item{web}
+	m.filtered = m.processes
+
+	res := &process.FreePortResult{Port: 8080, Freed: true, Holders: []process.PortHolder{{Item: web, Action: "SIGTERM"}}}
+	newModel, cmd := m.Update(portFreedMsg{result: res})
+	m = newModel.(model)
+	if web.Status != process.Killed {
+		t.Fatalf("expected holder to be marked killed")
+	}
+	if m.notice != res.Summary() {
+		t.Fatalf("notice = %q, want %q", m.notice, res.Summary())
+	}
+	if cmd == nil {
+		t.Fatalf("expected a refresh command")
+	}
+}
+
+func TestRefreshMergeKeepsMarkersAndCursor(t *testing.T) {
+	m := InitialModel("")
+	m.loaded = true
+	api := process.NewItem(1, "api", "test")
+	web := process.NewItem(2, "web", "test", 8080)
+	gone := process.NewItem(3, "worker", "test")
+	web.Status = process.Paused
+	m.processes = []*process.Item{api, web, gone}
+	m.filtered = m.filterProcesses("")
+	m.cursor = 1 // web
+
+	fresh := []*process.Item{
+		process.NewItem(0, "aaa", "test"),
+		process.NewItem(1, "api", "test"),
+		process.NewItem(2, "web", "test", 8080, 8443),
+	}
+	newModel, _ := m.Update(processesLoadedMsg{processes: fresh})
+	m = newModel.(model)
+
+	selected, ok := m.selectedProcess()
+	if !ok || selected != web {
+		t.Fatalf("expected cursor to stay on web, got %+v", selected)
+	}
+	if web.Status != process.Paused || len(web.Ports) != 2 {
+		t.Fatalf("expected web to keep Paused and take fresh ports, got %+v", web)
+	}
+	if gone.Status != process.Exited || len(m.filtered) != 4 {
+		t.Fatalf("expected worker to be shown as exited, got %v with %d rows", gone.Status, len(m.filtered))
+	}
+}
+
+func TestAutoRefreshTickNeverOverlapsScans(t *testing.T) {
+	m := InitialModelWithOptions(Options{RefreshInterval: time.Second})
+	if !m.autoRefresh || !m.refreshing {
+		t.Fatalf("expected auto-refresh on with the initial scan in flight")
+	}
+
+	// The first scan finishing starts the timer.
+	newModel, cmd := m.Update(processesLoadedMsg{})
+	m = newModel.(model)
+	if m.refreshing || cmd == nil {
+		t.Fatalf("expected an idle model with a pending tick")
+	}
+
+	// Ticks from a replaced timer are ignored.
+	newModel, cmd = m.Update(autoRefreshTickMsg{id: m.tickID - 1})
+	m = newModel.(model)
+	if m.refreshing || cmd != nil {
+		t.Fatalf("stale tick should be ignored")
+	}
+
+	// An open confirm dialog postpones the scan.
+	m.confirm = &confirmPrompt{pid: 1}
+	newModel, _ = m.Update(autoRefreshTickMsg{id: m.tickID})
+	m = newModel.(model)
+	if m.refreshing {
+		t.Fatalf("expected no scan while the confirm dialog is open")
+	}
+	m.confirm = nil
+
+	newModel, cmd = m.Update(autoRefreshTickMsg{id: m.tickID})
+	m = newModel.(model)
+	if !m.refreshing || cmd == nil {
+		t.Fatalf("expected the tick to start a scan")
+	}
+
+	// A manual refresh during the scan is queued rather than run in parallel.
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = newModel.(model)
+	if cmd != nil || !m.refreshQueued {
+		t.Fatalf("expected ctrl+r to be queued while a scan runs")
+	}
+	newModel, _ = m.Update(processesLoadedMsg{})
+	m = newModel.(model)
+	if !m.refreshing || m.refreshQueued {
+		t.Fatalf("expected the queued refresh to start when the scan finished")
+	}
+}
+
+func TestProcessPaneShowsUsageColumns(t *testing.T) {
+	m := InitialModel("")
+	busy := process.NewItem(1, "busy", "test")
+	busy.CPUPercent = 187.5
+	busy.RSS = 3 << 29 // 1.5 GiB
+	busy.Threads = 12
+	m.processes = []*process.Item{busy}
+	m.filtered = m.processes
+
+	pane := m.renderProcessPane()
+	for _, want := range []string{"187.5", "1.5G", " 12"} {
+		if !strings.Contains(pane, want) {
+			t.Fatalf("expected %q in process pane:\n%s", want, pane)
+		}
+	}
+	if got := compactBytes(900 << 10); got != "900K" {
+		t.Fatalf("compactBytes = %q, want 900K", got)
+	}
+}
+
+func TestSortKeysCycleAndKeepSelection(t *testing.T) {
+	m := InitialModel("")
+	m.loaded, m.refreshing = true, false
+	idle := process.NewItem(1, "idle", "test")
+	busy := process.NewItem(2, "busy", "test")
+	busy.CPUPercent = 90
+	m.processes = []*process.Item{idle, busy}
+	m.filtered = m.filterProcesses("")
+	m.cursor = 0 // idle
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = newModel.(model)
+	if m.sortKey != search.SortCPU || !m.sortDesc || m.filtered[0] != busy {
+		t.Fatalf("expected cpu sort with busy first, got %v desc=%v", m.sortKey, m.sortDesc)
+	}
+	if p, _ := m.selectedProcess(); p != idle {
+		t.Fatalf("expected the cursor to follow idle")
+	}
+	if !strings.Contains(m.renderHeader(), "[sort: cpu ↓]") {
+		t.Fatalf("expected the sort badge in the header: %q", m.renderHeader())
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	m = newModel.(model)
+	if m.sortDesc || m.filtered[0] != idle {
+		t.Fatalf("expected S to reverse the order")
+	}
+
+	// The sort survives a refresh.
+	newModel, _ = m.Update(processesLoadedMsg{processes: []*process.Item{process.NewItem(1, "idle", "test"), process.NewItem(2, "busy", "test")}})
+	m = newModel.(model)
+	if m.sortKey != search.SortCPU {
+		t.Fatalf("expected the sort to be kept across refreshes")
+	}
+}
+
+func TestStartColumnShowsAgeOrStartTime(t *testing.T) {
+	m := InitialModel("")
+	now := time.Now()
+	p := process.NewItem(1, "old", "test")
+	p.StartTime = now.Add(-3 * 24 * time.Hour)
+
+	if got := m.startColumn(p, now); got != "3d" {
+		t.Fatalf("age column = %q, want 3d", got)
+	}
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = newModel.(model)
+	if got, want := m.startColumn(p, now), process.FormatStartTime(p.StartTime, now); got != want {
+		t.Fatalf("absolute column = %q, want %q", got, want)
+	}
+}
+
+func TestCmdlineColumnToggle(t *testing.T) {
+	m := InitialModel("")
+	server := process.NewItem(1, "node", "dev")
+	server.Cmdline = "node server.js --port 3000"
+	m.processes = []*process.Item{server}
+	m.filtered = m.processes
+
+	if strings.Contains(m.renderProcessPane(), "server.js") {
+		t.Fatalf("command line should be hidden by default")
+	}
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = newModel.(model)
+	if !strings.Contains(m.renderProcessPane(), "server.js --port 3000") {
+		t.Fatalf("expected the arguments next to the name:\n%s", m.renderProcessPane())
+	}
+}
+
+func TestDepFilterUsesQueryTerms(t *testing.T) {
+	m := InitialModel("")
+	m.processes = []*process.Item{
+		{Pid: 1, PPid: 0, Executable: "init", User: "root", Status: process.Alive},
+		{Pid: 2, PPid: 1, Executable: "web", User: "test", Status: process.Alive, Ports: []uint32{8080}},
+		{Pid: 8080, PPid: 1, Executable: "job", User: "test", Status: process.Alive},
+	}
+	m.filtered = m.processes
+	m = m.enterDepMode(1)
+
+	m.textInput.SetValue("port:8080")
+	lines := applyDepFilters(m, buildDepLines(m))
+	if len(lines) != 1 || lines[0].pid != 2 {
+		t.Fatalf("expected only the listener, got %+v", lines)
+	}
+
+	m.textInput.SetValue("user:test job")
+	lines = applyDepFilters(m, buildDepLines(m))
+	if len(lines) != 1 || lines[0].pid != 8080 {
+		t.Fatalf("expected structured and free text to combine, got %+v", lines)
+	}
+}
+
+func TestDetailsTabsLoadLazilyOnce(t *testing.T) {
+	m := InitialModel("")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = newModel.(model)
+	m, _ = m.openProcessDetails(43)
+	requestID := m.detailsRequestID
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	m = newModel.(model)
+	if m.detailsTab != tabNetwork || cmd == nil {
+		t.Fatalf("expected switching to Network to start a load, got tab=%v cmd=%v", m.detailsTab, cmd)
+	}
+
+	// A response for an older request must not be cached.
+	newModel, _ = m.Update(detailsTabMsg{requestID: requestID - 1, tab: tabNetwork, data: []process.Socket{}})
+	m = newModel.(model)
+	if _, ok := m.detailsTabs[tabNetwork]; ok {
+		t.Fatalf("expected stale tab data to be ignored")
+	}
+
+	sockets := []process.Socket{{FD: 7, Proto: "tcp", Local: "127.0.0.1:8080", Remote: "127.0.0.1:51000", State: "ESTABLISHED"}}
+	newModel, _ = m.Update(detailsTabMsg{requestID: requestID, tab: tabNetwork, data: sockets})
+	m = newModel.(model)
+	if view := stripANSI(m.View()); !strings.Contains(view, "127.0.0.1:51000") || !strings.Contains(view, "ESTABLISHED") {
+		t.Fatalf("expected the Network tab to list the socket, got:\n%s", view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m = newModel.(model)
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newModel.(model)
+	if m.detailsTab != tabNetwork || cmd != nil {
+		t.Fatalf("expected returning to a loaded tab not to reload it, got tab=%v cmd=%v", m.detailsTab, cmd)
+	}
+}
+
+func TestDetailsLiveModeSamplesAndDropsStaleMetrics(t *testing.T) {
+	m := InitialModel("")
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = newModel.(model)
+	m, _ = m.openProcessDetails(43)
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	m = newModel.(model)
+	if !m.detailsLive || cmd == nil {
+		t.Fatalf("expected L to start live mode with a sample, got live=%v cmd=%v", m.detailsLive, cmd)
+	}
+	requestID, liveID := m.detailsRequestID, m.detailsLiveID
+
+	// A sample from a previous live session must be dropped.
+	newModel, cmd = m.Update(detailsMetricsMsg{requestID: requestID, liveID: liveID - 1, metrics: process.Metrics{RSS: 1}})
+	m = newModel.(model)
+	if len(m.detailsHistory) != 0 || cmd != nil {
+		t.Fatalf("expected stale metrics to be ignored, got history=%v cmd=%v", m.detailsHistory, cmd)
+	}
+
+	now := time.Now()
+	for i, rss := range []uint64{100 << 20, 200 << 20} {
+		newModel, cmd = m.Update(detailsMetricsMsg{requestID: requestID, liveID: liveID, metrics: process.Metrics{
+			At: now.Add(time.Duration(i) * time.Second), CPUPercent: 12.5, RSS: rss, FDs: 9, Threads: 3,
+		}})
+		m = newModel.(model)
+		if cmd == nil {
+			t.Fatalf("expected each sample to schedule the next tick")
+		}
+	}
+	if len(m.detailsHistory) != 2 {
+		t.Fatalf("expected 2 samples in history, got %d", len(m.detailsHistory))
+	}
+	if view := stripANSI(m.View()); !strings.Contains(view, "CPU") || !strings.Contains(view, "200M") {
+		t.Fatalf("expected the live metrics line in the header, got:\n%s", view)
+	}
+	if line := m.renderDetailsMetrics(40); lipgloss.Height(line) != 1 || lipgloss.Width(line) > 40 {
+		t.Fatalf("expected the metrics line to be cut to one 40-cell row, got %d cells:\n%s", lipgloss.Width(line), line)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	m = newModel.(model)
+	newModel, cmd = m.Update(detailsLiveTickMsg{requestID: requestID, liveID: liveID})
+	m = newModel.(model)
+	if m.detailsLive || cmd != nil {
+		t.Fatalf("expected pausing live mode to stop the tick chain, got live=%v cmd=%v", m.detailsLive, cmd)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(model)
+	if m.detailsHistory != nil {
+		t.Fatalf("expected leaving details to clear the live history")
+	}
+}
+
+func TestSparklineScalesToRange(t *testing.T) {
+	if got := sparkline([]float64{1, 2, 3, 4, 5, 6, 7, 8}, 8); got != "▁▂▃▄▅▆▇█" {
+		t.Fatalf("sparkline = %q", got)
+	}
+	if got := sparkline([]float64{5, 5}, 4); got != "  ▁▁" {
+		t.Fatalf("flat sparkline = %q", got)
+	}
+	if got := sparkline([]float64{0, 1, 2, 3}, 2); got != "▁█" {
+		t.Fatalf("expected only the newest values to be drawn, got %q", got)
+	}
+}
+
+func TestBatchKillConfirmsEveryMarkedTarget(t *testing.T) {
+	m := InitialModel("")
+	m.processes = []*process.Item{
+		process.NewItem(11, "worker", "test"),
+		process.NewItem(12, "worker", "test"),
+		process.NewItem(13, "api", "test"),
+	}
+	m.processes[2].ContainerName = "api-1"
+	m.filtered = m.processes
+
+	// space marks the row and moves down; I inverts the marks on every filtered row.
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = newModel.(model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+	m = newModel.(model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+	m = newModel.(model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = newModel.(model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = newModel.(model)
+	if len(m.selected) != 3 {
+		t.Fatalf("expected all 3 rows marked, got %d", len(m.selected))
+	}
+	if view := stripANSI(m.View()); !strings.Contains(view, "[3 selected]") {
+		t.Fatalf("expected the selection count in the header, got:\n%s", view)
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(model)
+	if m.confirm == nil || len(m.confirm.targets) != 3 || cmd != nil {
+		t.Fatalf("expected one confirm dialog for the whole selection, got %+v", m.confirm)
+	}
+	view := stripANSI(m.View())
+	for _, want := range []string{"Targets (3):", "worker (11)", "worker (12)", "api-1 (13, docker stop)"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected the confirm dialog to list %q, got:\n%s", want, view)
+		}
+	}
+
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(model)
+	if cmd == nil || m.selected != nil {
+		t.Fatalf("expected confirming to start the batch and clear the marks")
+	}
+
+	if m.processes[0].Status != process.Killed || m.processes[1].Status != process.Killed {
+		t.Fatalf("expected the process targets to be marked killed while the kill is verified")
+	}
+
+	// The container went through docker stop; the processes through TERM→KILL.
+	targets := m.processes
+	newModel, _ = m.Update(batchDoneMsg{op: "kill", sig: syscall.SIGTERM, results: []batchResult{
+		{target: newBatchTarget(targets[0]), terminated: &process.TerminateResult{Action: "SIGTERM", Outcome: process.OutcomeExited, Elapsed: 120 * time.Millisecond}},
+		{target: newBatchTarget(targets[1]), terminated: &process.TerminateResult{Action: "SIGTERM → SIGKILL", Outcome: process.OutcomeAlive, Elapsed: 5 * time.Second}},
+		{target: batchTarget{pid: 13, key: targets[2].Key(), name: "api", containerName: "api-1"}, err: errors.New("no such container")},
+	}})
+	m = newModel.(model)
+	if m.processes[0].Status != process.Exited || m.processes[1].Status != process.Survived || m.processes[2].Status != process.Alive {
+		t.Fatalf("expected exited, survived and unchanged, got %v %v %v", m.processes[0].Status, m.processes[1].Status, m.processes[2].Status)
+	}
+	for _, want := range []string{"kill: 1 of 3 succeeded", "✓ worker (11): exited after SIGTERM in 120ms", "✗ worker (12): still alive after SIGTERM → SIGKILL (5s)", "✗ 🐳 api-1 (13, docker stop): no such container"} {
+		if !strings.Contains(m.notice, want) {
+			t.Fatalf("expected the per-target report to contain %q, got:\n%s", want, m.notice)
+		}
+	}
+	if view := stripANSI(m.View()); !strings.Contains(view, "[!]") || !strings.Contains(view, "[X]") {
+		t.Fatalf("expected the list to show the outcome markers, got:\n%s", view)
+	}
+}
+
+func TestBatchResultSkipsReusedPID(t *testing.T) {
+	m := InitialModel("")
+	m.loaded = true
+	old := process.NewItem(41, "worker", "test")
+	old.StartTime = time.UnixMilli(1_000_000)
+	target := newBatchTarget(old)
+
+	// While the kill was verified the worker exited and its PID went to a new process.
+	successor := process.NewItem(41, "shell", "test")
+	successor.StartTime = time.UnixMilli(2_000_000)
+	m.processes = []*process.Item{successor}
+	m.filtered = m.processes
+
+	newModel, _ := m.Update(batchDoneMsg{op: "kill", sig: syscall.SIGTERM, results: []batchResult{
+		{target: target, terminated: &process.TerminateResult{Action: "SIGTERM", Outcome: process.OutcomeExited}},
+	}})
+	m = newModel.(model)
+	if successor.Status != process.Alive {
+		t.Fatalf("expected the process now holding pid 41 to stay alive, got %v", successor.Status)
+	}
+}
+
+func TestSelectionSurvivesRefreshUntilProcessExits(t *testing.T) {
+	m := InitialModel("")
+	m.loaded = true
+	m.processes = []*process.Item{process.NewItem(21, "a", "test"), process.NewItem(22, "b", "test")}
+	m.filtered = m.processes
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = newModel.(model)
+
+	newModel, _ = m.Update(processesLoadedMsg{processes: []*process.Item{process.NewItem(21, "a", "test")}})
+	m = newModel.(model)
+	if len(m.selected) != 1 || !m.isSelected(m.processes[0]) {
+		t.Fatalf("expected only the surviving process to stay marked, got %v", m.selected)
+	}
+}
+
+func TestSignalMenuSendsPickedSignalAndRemembersIt(t *testing.T) {
+	m := InitialModel("")
+	m.processes = []*process.Item{process.NewItem(31, "nginx", "test")}
+	m.filtered = m.processes
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+	m = newModel.(model)
+	if m.signalMenu == nil || m.signalMenu.cursor != 0 {
+		t.Fatalf("expected K to open the signal menu on the first signal")
+	}
+	for i := 0; i < 2; i++ {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = newModel.(model)
+	}
+	picked := m.signalMenu.signals[2]
+	if view := stripANSI(m.View()); !strings.Contains(view, picked.Description) {
+		t.Fatalf("expected the menu to describe each signal, got:\n%s", view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(model)
+	if m.signalMenu != nil || m.confirm == nil || m.confirm.sig != picked.Signal || m.lastSignal != picked.Signal {
+		t.Fatalf("expected choosing a signal to open its confirm prompt, got %+v", m.confirm)
+	}
+	if view := stripANSI(m.View()); !strings.Contains(view, "Signal: "+picked.Name) {
+		t.Fatalf("expected the confirm prompt to show %s, got:\n%s", picked.Name, view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+	m = newModel.(model)
+	if m.signalMenu.cursor != 2 {
+		t.Fatalf("expected the menu to reopen on the last used signal, got cursor %d", m.signalMenu.cursor)
+	}
+}
+
+func TestSignalStatus(t *testing.T) {
+	if got := signalStatus(syscall.SIGKILL, process.Alive); got != process.Killed {
+		t.Fatalf("SIGKILL should mark the process killed, got %v", got)
+	}
+	if got := signalStatus(syscall.Signal(1), process.Paused); got != process.Paused {
+		t.Fatalf("a non-terminating signal should keep the status, got %v", got)
+	}
+}
+
+func TestSubtreeKillListsDescendantsLeavesFirst(t *testing.T) {
+	m := InitialModel("")
+	m.processes = []*process.Item{
+		{Pid: 1, PPid: 0, Executable: "init", Status: process.Alive},
+		{Pid: 10, PPid: 1, Executable: "vite", Status: process.Alive},
+		{Pid: 11, PPid: 10, Executable: "esbuild", Status: process.Alive},
+		{Pid: 12, PPid: 10, Executable: "watcher", Status: process.Alive},
+		{Pid: 13, PPid: 12, Executable: "fsnotify", Status: process.Alive},
+		{Pid: 14, PPid: 10, Executable: "gone", Status: process.Exited},
+		{Pid: 20, PPid: 1, Executable: "other", Status: process.Alive},
+	}
+	m.filtered = m.processes
+	m = m.enterDepMode(10)
+	m = m.toggleSelected(m.processes[6])
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	m = newModel.(model)
+	if m.confirm == nil || !m.confirm.tree {
+		t.Fatalf("expected a subtree confirm dialog, got %+v", m.confirm)
+	}
+	var pids []int32
+	for _, tg := range m.confirm.targets {
+		pids = append(pids, tg.pid)
+	}
+	if fmt.Sprint(pids) != "[11 13 12 10]" {
+		t.Fatalf("expected live descendants leaves first, got %v", pids)
+	}
+	view := stripANSI(m.View())
+	for _, want := range []string{"Subtree of vite (10), children first", "Processes (4):", "fsnotify (13)"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected the confirm dialog to show %q, got:\n%s", want, view)
+		}
+	}
+
+	// Confirming leaves the unrelated mark alone and marks the subtree as pending.
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(model)
+	if cmd == nil || len(m.selected) != 1 {
+		t.Fatalf("expected a kill command and the unrelated mark kept, got cmd=%v selected=%d", cmd, len(m.selected))
+	}
+	if m.findProcess(13).Status != process.Killed || m.findProcess(20).Status != process.Alive {
+		t.Fatal("expected only the subtree to be pending")
+	}
+}
+
+func TestConfirmTargetListScrollsToEveryTarget(t *testing.T) {
+	m := InitialModel("")
+	for i := 0; i < 15; i++ {
+		m.processes = append(m.processes, process.NewItem(100+i, "worker", "test"))
+	}
+	m.filtered = m.processes
+	m = m.selectAll(m.processes).confirmBatch("kill", syscall.SIGTERM)
+
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "Targets (15):") || !strings.Contains(view, "1–12 of 15") || strings.Contains(view, "(114)") {
+		t.Fatalf("expected the first page of targets, got:\n%s", view)
+	}
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	m = newModel.(model)
+	view = stripANSI(m.View())
+	if !strings.Contains(view, "worker (114)") || !strings.Contains(view, "4–15 of 15") || m.confirm == nil {
+		t.Fatalf("expected scrolling to reach the last target, got:\n%s", view)
+	}
+}
+
+func TestManagerStopPreviewsTheCommand(t *testing.T) {
+	m := InitialModel("")
+	m.processes = []*process.Item{process.NewItem(42, "nginx", "root")}
+	m.filtered = m.processes
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	m = newModel.(model)
+	if cmd == nil || !strings.Contains(m.notice, "nginx (42)") {
+		t.Fatalf("expected a background lookup with a progress notice, got %q", m.notice)
+	}
+
+	newModel, _ = m.Update(managerLookupMsg{id: m.managerLookupID, pid: 42, name: "nginx", err: errors.New("nginx (42) is not run by systemd, pm2, supervisord or docker (source: shell)")})
+	m = newModel.(model)
+	if m.confirm != nil || !strings.Contains(m.notice, "source: shell") {
+		t.Fatalf("expected the lookup error as a notice, got %q", m.notice)
+	}
+
+	stop := &process.ManagerCommand{Manager: "systemd", Args: []string{"systemctl", "stop", "nginx.service"}}
+	// A result for a row the cursor has left, or from a superseded lookup, is dropped.
+	newModel, _ = m.Update(managerLookupMsg{id: m.managerLookupID, pid: 7, name: "bash", cmd: stop})
+	m = newModel.(model)
+	if m.confirm != nil {
+		t.Fatal("expected a lookup for another process to be dropped")
+	}
+	newModel, _ = m.Update(managerLookupMsg{id: m.managerLookupID - 1, pid: 42, name: "nginx", cmd: stop})
+	m = newModel.(model)
+	if m.confirm != nil {
+		t.Fatal("expected a superseded lookup to be dropped")
+	}
+	newModel, _ = m.Update(managerLookupMsg{id: m.managerLookupID, pid: 42, name: "nginx", cmd: stop})
+	m = newModel.(model)
+	if m.confirm == nil || m.confirm.manager != stop {
+		t.Fatalf("expected a confirm dialog for the manager command, got %+v", m.confirm)
+	}
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "Command: systemctl stop nginx.service") || strings.Contains(view, "Signal:") {
+		t.Fatalf("expected the exact command instead of a signal, got:\n%s", view)
+	}
+
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(model)
+	if cmd == nil || m.confirm != nil {
+		t.Fatal("expected confirming to run the command")
+	}
+	newModel, _ = m.Update(managerStoppedMsg{pid: 42, cmd: *stop})
+	m = newModel.(model)
+	if m.processes[0].Status != process.Killed || !strings.Contains(m.notice, "stopped via `systemctl stop nginx.service`") {
+		t.Fatalf("expected the process marked and the command reported, got %v %q", m.processes[0].Status, m.notice)
+	}
+}
+
+func TestRespawnAfterKillIsMarkedAndExplained(t *testing.T) {
+	m := InitialModel("")
+	m.loaded = true
+	killedAt := time.Now()
+	old := process.NewItem(30, "nginx", "root", 80)
+	old.Cmdline, old.StartTime = "nginx -g daemon off;", killedAt.Add(-time.Hour)
+	m.processes = []*process.Item{old}
+	m.filtered = m.processes
+
+	newModel, cmd := m.Update(batchDoneMsg{op: "kill", sig: syscall.SIGTERM, started: killedAt, results: []batchResult{{
+		target:     newBatchTarget(old),
+		terminated: &process.TerminateResult{Item: old, Action: "SIGTERM", Outcome: process.OutcomeExited},
+	}}})
+	m = newModel.(model)
+	if cmd == nil || len(m.departed) != 1 || !m.respawnWatch {
+		t.Fatalf("expected the kill to start a respawn watch, got %v", m.departed)
+	}
+
+	fresh := process.NewItem(31, "nginx", "root", 80)
+	fresh.Cmdline, fresh.StartTime = old.Cmdline, killedAt.Add(900*time.Millisecond)
+	newModel, cmd = m.Update(processesLoadedMsg{processes: []*process.Item{fresh}})
+	m = newModel.(model)
+	if cmd == nil || !m.isRespawned(m.processes[0]) || len(m.departed) != 0 {
+		t.Fatalf("expected pid 31 to be recognised as a respawn, got %v", m.respawns)
+	}
+	if !strings.Contains(m.notice, "nginx respawned as pid 31 900ms after the kill (same command line)") {
+		t.Fatalf("unexpected notice %q", m.notice)
+	}
+	if view := stripANSI(m.View()); !strings.Contains(view, "[R]") {
+		t.Fatalf("expected the respawned process to be marked, got:\n%s", view)
+	}
+
+	cause := process.RespawnCause{Source: why.Source{Type: why.SourceSystemd, Name: "systemd"}, Unit: "nginx.service"}
+	newModel, _ = m.Update(respawnCauseMsg{key: m.processes[0].Key(), cause: cause})
+	m = newModel.(model)
+	if !strings.Contains(m.notice, "restarted by systemd") || !strings.Contains(m.notice, "Press M") {
+		t.Fatalf("expected the supervisor in the notice, got %q", m.notice)
+	}
+}
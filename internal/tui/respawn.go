@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// respawn.go 实现 kill 之后的“重生”检测。
+//
+// 被 kill 的进程会记录为 `process.Departed`，在 respawnWindow 内定期刷新列表；若出现一个在
+// kill 之后启动、命令行相同（或监听同一端口）的新进程，就把它标记为重生（列表中的 `R`），并在
+// 后台分析它的来源，在提示区说明是谁把它拉起来的（systemd、pm2、父 shell 等）。
+
+const (
+	// respawnWindow 是 kill 之后观察重生的时长。
+	respawnWindow = 30 * time.Second
+	// respawnPollInterval 是观察期内刷新列表的间隔（自动刷新开启时也会照常刷新）。
+	respawnPollInterval = 2 * time.Second
+)
+
+// respawnInfo 记录一个被识别为重生的进程。
+type respawnInfo struct {
+	respawn process.Respawn
+	cause   string // 拉起它的管理者或父进程；分析完成前为空。
+	managed bool   // cause 是 `M` 可以操作的管理者。
+}
+
+// respawnTickMsg 在观察期内定期触发一次刷新。
+type respawnTickMsg struct{}
+
+// respawnCauseMsg 携带对重生进程来源的分析结果。
+type respawnCauseMsg struct {
+	key   process.Key
+	cause process.RespawnCause
+	err   error
+}
+
+// trackDeparted 记录在 at 被 kill 的进程；尚未在观察时开始观察期的定期刷新。
+func (m model) trackDeparted(items []*process.Item, at time.Time) (model, tea.Cmd) {
+	for _, it := range items {
+		m.departed = append(m.departed, process.NewDeparted(it, at))
+	}
+	if len(items) == 0 || m.respawnWatch {
+		return m, nil
+	}
+	m.respawnWatch = true
+	return m, respawnTick()
+}
+
+func respawnTick() tea.Cmd {
+	return tea.Tick(respawnPollInterval, func(time.Time) tea.Msg { return respawnTickMsg{} })
+}
+
+// updateRespawnTick 丢弃超出观察期的记录；仍有记录时刷新列表并安排下一次计时。
+func (m model) updateRespawnTick() (tea.Model, tea.Cmd) {
+	now := time.Now()
+	kept := m.departed[:0]
+	for _, d := range m.departed {
+		if now.Sub(d.At) < respawnWindow {
+			kept = append(kept, d)
+		}
+	}
+	m.departed = kept
+	if len(m.departed) == 0 {
+		m.respawnWatch = false
+		return m, nil
+	}
+	if m.refreshPaused() || m.refreshing {
+		return m, respawnTick()
+	}
+	m, cmd := m.startRefresh()
+	return m, tea.Batch(cmd, respawnTick())
+}
+
+// detectRespawns 在每次刷新合并后查找重生的进程，并为新发现的进程发起来源分析。
+// 已消失进程的重生记录随之清除。
+func (m model) detectRespawns() (model, tea.Cmd) {
+	for key := range m.respawns {
+		if it := m.findByKey(key); it == nil || it.Status == process.Exited {
+			delete(m.respawns, key)
+		}
+	}
+	found := process.FindRespawns(m.departed, m.processes)
+	if len(found) == 0 {
+		return m, nil
+	}
+	if m.respawns == nil {
+		m.respawns = make(map[process.Key]*respawnInfo)
+	}
+
+	var cmds []tea.Cmd
+	for _, r := range found {
+		key := r.Item.Key()
+		m.respawns[key] = &respawnInfo{respawn: r}
+		for i, d := range m.departed {
+			if d.Key == r.Of.Key {
+				m.departed = append(m.departed[:i], m.departed[i+1:]...)
+				break
+			}
+		}
+		pid := r.Item.Pid
+		cmds = append(cmds, func() tea.Msg {
+			cause, err := process.FindRespawnCause(pid)
+			return respawnCauseMsg{key: key, cause: cause, err: err}
+		})
+	}
+	m.notice = respawnNotice(m.respawns[found[len(found)-1].Item.Key()])
+	return m, tea.Batch(cmds...)
+}
+
+// updateRespawnCause 记录重生进程的来源，并更新提示。
+func (m model) updateRespawnCause(msg respawnCauseMsg) (tea.Model, tea.Cmd) {
+	info, ok := m.respawns[msg.key]
+	if !ok {
+		return m, nil
+	}
+	if msg.err != nil {
+		info.cause = "an unknown parent"
+	} else {
+		info.cause, info.managed = msg.cause.String(), msg.cause.Managed()
+	}
+	m.notice = respawnNotice(info)
+	return m, nil
+}
+
+// respawnNotice 描述一次重生，例如
+// "↻ nginx respawned as pid 1301 0.9s after the kill (same command line), restarted by systemd (nginx.service)"。
+func respawnNotice(info *respawnInfo) string {
+	r := info.respawn
+	msg := fmt.Sprintf("↻ %s respawned as pid %d %s after the kill (%s)", r.Item.Executable, r.Item.Pid, r.Delay().Round(100*time.Millisecond), r.Reason)
+	switch {
+	case info.cause == "":
+		return msg + "; looking up who restarted it…"
+	case info.managed:
+		return fmt.Sprintf("%s, restarted by %s. Press M to stop it through its manager.", msg, info.cause)
+	}
+	return fmt.Sprintf("%s, restarted by %s.", msg, info.cause)
+}
+
+// findByKey 按身份（PID + 启动时间）查找进程。
+func (m model) findByKey(key process.Key) *process.Item {
+	for _, it := range m.processes {
+		if it.Key() == key {
+			return it
+		}
+	}
+	return nil
+}
+
+// isRespawned 报告 it 是否被识别为某个被 kill 进程的重生。
+func (m model) isRespawned(it *process.Item) bool {
+	_, ok := m.respawns[it.Key()]
+	return ok
+}
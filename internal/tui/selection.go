@@ -0,0 +1,362 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// selection.go 实现主列表与 T 模式共用的多选与批量操作。
+//
+// 选中状态按 `process.Key`（PID + 启动时间）记录，因此在刷新之间保持，且不会因 PID 复用
+// 而误选到新的进程。有选中项时，kill / pause / resume 作用于整个选择：先弹出一个列出所有
+// 目标的确认对话框，确认后在一个命令中依次执行，最后逐个报告结果。
+
+// batchTarget 是批量操作中的一个目标。确认时从进程条目复制出来，避免在后台 Goroutine 中读取条目。
+type batchTarget struct {
+	pid           int32
+	key           process.Key // 结果返回时按身份找回条目，PID 已被复用时不会更新到新进程上。
+	name          string
+	containerName string         // 非空时 kill 使用 docker stop。
+	startTime     time.Time      // 用于 kill 后确认进程是否真正退出，避免被复用的 PID 误导。
+	status        process.Status // 操作前的状态；信号发送失败时恢复为该状态。
+}
+
+// newBatchTarget 从进程条目复制出一个目标。
+func newBatchTarget(it *process.Item) batchTarget {
+	return batchTarget{pid: it.Pid, key: it.Key(), name: it.Executable, startTime: it.StartTime, status: it.Status}
+}
+
+// label 返回目标在确认对话框和结果中的显示名称。
+func (t batchTarget) label() string {
+	if t.containerName != "" {
+		return fmt.Sprintf("🐳 %s (%d, docker stop)", t.containerName, t.pid)
+	}
+	return fmt.Sprintf("%s (%d)", t.name, t.pid)
+}
+
+// batchResult 是批量操作中单个目标的结果。terminated 非 nil 时表示该目标经过
+// TERM→KILL 策略并确认了最终结果（已退出、僵尸或仍存活）。
+type batchResult struct {
+	target     batchTarget
+	err        error
+	terminated *process.TerminateResult
+}
+
+// batchDoneMsg 携带一次批量操作的全部结果。
+type batchDoneMsg struct {
+	op      string
+	sig     syscall.Signal // 成功的目标按 signalStatus 更新状态。
+	results []batchResult
+	started time.Time // 操作开始的时间，用于识别 kill 之后重生的进程。
+}
+
+// isSelected 报告条目是否被选中。
+func (m model) isSelected(it *process.Item) bool {
+	return m.selected[it.Key()]
+}
+
+// toggleSelected 切换单个条目的选中状态。
+func (m model) toggleSelected(it *process.Item) model {
+	if m.selected == nil {
+		m.selected = make(map[process.Key]bool)
+	}
+	key := it.Key()
+	if m.selected[key] {
+		delete(m.selected, key)
+	} else {
+		m.selected[key] = true
+	}
+	return m
+}
+
+// selectAll 选中 items 中的全部条目；若它们已全部选中，则取消选中，便于用同一个键清空。
+func (m model) selectAll(items []*process.Item) model {
+	all := len(items) > 0
+	for _, it := range items {
+		if !m.isSelected(it) {
+			all = false
+			break
+		}
+	}
+	if m.selected == nil {
+		m.selected = make(map[process.Key]bool)
+	}
+	for _, it := range items {
+		if all {
+			delete(m.selected, it.Key())
+		} else {
+			m.selected[it.Key()] = true
+		}
+	}
+	return m
+}
+
+// invertSelection 反转 items 中每个条目的选中状态；items 之外的选中项保持不变。
+func (m model) invertSelection(items []*process.Item) model {
+	for _, it := range items {
+		m = m.toggleSelected(it)
+	}
+	return m
+}
+
+// clearSelection 清空选择。
+func (m model) clearSelection() model {
+	m.selected = nil
+	return m
+}
+
+// pruneSelection 在刷新后移除已退出或已消失进程的选中记录，使计数与可操作的目标一致。
+func (m model) pruneSelection() model {
+	if len(m.selected) == 0 {
+		return m
+	}
+	alive := make(map[process.Key]bool, len(m.selected))
+	for _, it := range m.processes {
+		key := it.Key()
+		if m.selected[key] && it.Status != process.Exited {
+			alive[key] = true
+		}
+	}
+	m.selected = alive
+	return m
+}
+
+// selectedItems 按进程列表顺序返回所有被选中的条目。
+func (m model) selectedItems() []*process.Item {
+	if len(m.selected) == 0 {
+		return nil
+	}
+	var out []*process.Item
+	for _, it := range m.processes {
+		if m.selected[it.Key()] && it.Status != process.Exited {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// visibleDepItems 返回 T 模式中当前可见（已应用过滤）的进程条目，用于全选与反选。
+func (m model) visibleDepItems() []*process.Item {
+	var out []*process.Item
+	for _, ln := range applyDepFilters(m, buildDepLines(m)) {
+		if ln.pid == 0 {
+			continue
+		}
+		if it := m.findProcess(ln.pid); it != nil {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// confirmBatch 为当前选择构建批量操作的确认对话框。resume 只作用于已暂停的进程；
+// 与单个 kill 一样，容器在 kill 时改用 docker stop，pause/resume 则直接向进程发送信号。
+func (m model) confirmBatch(op string, sig syscall.Signal) model {
+	var targets []batchTarget
+	for _, it := range m.selectedItems() {
+		if op == "resume" && it.Status != process.Paused {
+			continue
+		}
+		t := newBatchTarget(it)
+		if op == "kill" {
+			t.containerName = it.ContainerName
+		}
+		targets = append(targets, t)
+	}
+	if len(targets) == 0 {
+		m.notice = fmt.Sprintf("nothing to %s in the selection", op)
+		return m
+	}
+	m.confirm = &confirmPrompt{op: op, sig: sig, targets: targets}
+	return m
+}
+
+// runBatch 是批量操作的命令工厂。它在后台依次处理每个目标，单个目标失败不会中断其余目标，
+// 全部完成后返回 `batchDoneMsg`。kill 非 nil 时，进程目标改用 `process.Terminate`：
+// 按目标顺序发送 SIGTERM，在宽限期内观察它们是否退出，必要时升级为 SIGKILL。
+// pgid 非零时信号通过 kill(-pgid) 发给整个进程组，targets 只用于观察和报告结果。
+func runBatch(op string, targets []batchTarget, sig syscall.Signal, kill *process.TerminateOptions, pgid int) tea.Cmd {
+	return func() tea.Msg {
+		started := time.Now()
+		results := make([]batchResult, len(targets))
+		var items []*process.Item
+		var indexes []int
+		for i, t := range targets {
+			results[i].target = t
+			switch {
+			case t.containerName != "":
+				results[i].err = process.StopContainer(t.containerName)
+			case kill != nil:
+				items = append(items, &process.Item{Pid: t.pid, Executable: t.name, StartTime: t.startTime})
+				indexes = append(indexes, i)
+			default:
+				results[i].err = process.SignalItem(&process.Item{Pid: t.pid, StartTime: t.startTime}, sig)
+			}
+		}
+		if len(items) > 0 {
+			var terminated []process.TerminateResult
+			if pgid != 0 {
+				terminated = process.TerminateGroup(context.Background(), pgid, items, *kill)
+			} else {
+				terminated = process.Terminate(context.Background(), items, *kill)
+			}
+			for j, res := range terminated {
+				results[indexes[j]].terminated = &res
+				results[indexes[j]].err = res.Err
+			}
+		}
+		return batchDoneMsg{op: op, sig: sig, results: results, started: started}
+	}
+}
+
+// startBatch 执行已确认的批量操作。kill 会先把进程目标标记为 Killed（等待确认退出），
+// 结果返回后再更新为最终状态。pgid 含义同 runBatch。
+func (m model) startBatch(op string, targets []batchTarget, sig syscall.Signal, pgid int) (model, tea.Cmd) {
+	if op != "kill" {
+		m.notice = fmt.Sprintf("%s: running on %d targets…", op, len(targets))
+		return m, runBatch(op, targets, sig, nil, 0)
+	}
+	m.notice = fmt.Sprintf("kill: waiting up to %s for %d targets to exit…", m.killOptions.Grace, len(targets))
+	if len(targets) == 1 {
+		m.notice = fmt.Sprintf("kill: waiting up to %s for %s to exit…", m.killOptions.Grace, targets[0].label())
+	}
+	for _, t := range targets {
+		if t.containerName != "" {
+			continue
+		}
+		if it := m.findByKey(t.key); it != nil {
+			it.Status = process.Killed
+		}
+	}
+	opts := m.killOptions
+	return m, runBatch(op, targets, sig, &opts, pgid)
+}
+
+// resultStatus 返回目标在操作后应显示的状态，以及该目标是否算作成功。
+func (r batchResult) resultStatus(sig syscall.Signal, current process.Status) (process.Status, bool) {
+	if r.terminated != nil {
+		switch {
+		case r.terminated.Outcome == process.OutcomeExited:
+			return process.Exited, true
+		case r.terminated.Outcome == process.OutcomeZombie:
+			return process.Zombie, true
+		case r.err != nil:
+			return r.target.status, false
+		default:
+			return process.Survived, false
+		}
+	}
+	if r.err != nil {
+		return current, false
+	}
+	return signalStatus(sig, current), true
+}
+
+// describe 返回单个目标结果的一行描述。
+func (r batchResult) describe(ok bool) string {
+	mark := "✓"
+	if !ok {
+		mark = "✗"
+	}
+	switch {
+	case r.terminated != nil:
+		return fmt.Sprintf("%s %s: %s", mark, r.target.label(), r.terminated.Summary())
+	case r.err != nil:
+		return fmt.Sprintf("%s %s: %v", mark, r.target.label(), r.err)
+	}
+	return mark + " " + r.target.label()
+}
+
+// updateBatchDone 把每个目标更新为其结果状态，并在提示区逐个列出结果。单个目标时只显示一行。
+// 列表随后重新过滤：kill 期间被标记为 Killed 而隐藏的进程，如果仍然存活会重新出现。
+// 成功 kill 的进程开始重生观察。
+func (m model) updateBatchDone(msg batchDoneMsg) (tea.Model, tea.Cmd) {
+	succeeded := 0
+	lines := make([]string, 0, len(msg.results)+1)
+	var killed []*process.Item
+	for _, r := range msg.results {
+		it := m.findByKey(r.target.key)
+		current := r.target.status
+		if it != nil {
+			current = it.Status
+		}
+		status, ok := r.resultStatus(msg.sig, current)
+		if it != nil {
+			it.Status = status
+			if ok && msg.op == "kill" {
+				killed = append(killed, it)
+			}
+		}
+		if ok {
+			succeeded++
+		}
+		lines = append(lines, r.describe(ok))
+	}
+	if len(lines) == 1 {
+		m.notice = lines[0]
+	} else {
+		for i := range lines {
+			lines[i] = "  " + lines[i]
+		}
+		summary := fmt.Sprintf("%s: %d of %d succeeded", msg.op, succeeded, len(msg.results))
+		m.notice = strings.Join(append([]string{summary}, lines...), "\n")
+	}
+	return m.refilterKeepingSelection().trackDeparted(killed, msg.started)
+}
+
+const (
+	// defaultConfirmListHeight 是收到窗口尺寸之前确认对话框目标列表的可见行数。
+	defaultConfirmListHeight = 12
+	// confirmChromeHeight 是确认对话框中目标列表以外的行数（标题、边框、操作、信号与帮助行）。
+	confirmChromeHeight = 12
+)
+
+// renderBatchTargets 渲染确认对话框中的目标列表，heading 为列表标题（如 "Targets"）。
+// 目标多于 height 行时，列表放入一个从第 offset 行开始的 viewport，并在下方提示滚动位置，
+// 因此每个目标都可以通过滚动看到。
+func renderBatchTargets(heading string, targets []batchTarget, height, offset int) string {
+	lines := make([]string, len(targets))
+	width := 0
+	for i, t := range targets {
+		lines[i] = "  " + t.label()
+		width = max(width, lipgloss.Width(lines[i]))
+	}
+	head := fmt.Sprintf("%s (%d):", heading, len(targets))
+	if height <= 0 || len(lines) <= height {
+		return head + "\n" + strings.Join(lines, "\n")
+	}
+	vp := viewport.New(width, height)
+	vp.SetContent(strings.Join(lines, "\n"))
+	vp.SetYOffset(offset)
+	first := vp.YOffset + 1
+	last := minInt(vp.YOffset+height, len(lines))
+	position := faintStyle.Render(fmt.Sprintf("  %d–%d of %d • up/down/pgup/pgdn: scroll", first, last, len(lines)))
+	return head + "\n" + vp.View() + "\n" + position
+}
+
+// scrollConfirm 滚动确认对话框中的目标列表，并把偏移限制在列表范围内。
+func (m model) scrollConfirm(delta int) model {
+	if m.confirm == nil {
+		return m
+	}
+	limit := max(len(m.confirm.targets)-m.confirmListHeight, 0)
+	m.confirmScroll = min(max(m.confirmScroll+delta, 0), limit)
+	return m
+}
+
+// selectionMark 返回行首的选中标记列。
+func (m model) selectionMark(it *process.Item) string {
+	if it != nil && m.isSelected(it) {
+		return selectedMarkStyle.Render("●") + " "
+	}
+	return "  "
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// signalMenu 是信号选择菜单（`K`）的状态。菜单列出当前平台可用的信号，选中后
+// 走与其他操作相同的确认对话框；有多选时作用于整个选择。
+type signalMenu struct {
+	signals   []process.SignalInfo
+	cursor    int
+	pid       int32     // 单个目标的 PID；有多选时为 0。
+	startTime time.Time // 单个目标的启动时间，用于确认 PID 未被复用。
+	name      string    // 单个目标的名称，或多选目标的描述。
+	batch     bool
+}
+
+// openSignalMenu 为 it（或当前多选）打开信号菜单，光标停在上一次使用的信号上。
+func (m model) openSignalMenu(it *process.Item) model {
+	menu := &signalMenu{signals: process.Signals()}
+	if n := len(m.selectedItems()); n > 0 {
+		menu.batch = true
+		menu.name = fmt.Sprintf("%d marked processes", n)
+	} else if it != nil {
+		menu.pid = it.Pid
+		menu.startTime = it.StartTime
+		menu.name = it.Executable
+	} else {
+		return m
+	}
+	for i, s := range menu.signals {
+		if s.Signal == m.lastSignal {
+			menu.cursor = i
+		}
+	}
+	m.signalMenu = menu
+	return m
+}
+
+// updateSignalMenuKey 处理信号菜单显示时的按键事件。
+func (m model) updateSignalMenuKey(msg tea.KeyMsg) (model, tea.Cmd) {
+	menu := m.signalMenu
+	switch msg.String() {
+	case "up", "k":
+		if menu.cursor > 0 {
+			menu.cursor--
+		}
+	case "down", "j":
+		if menu.cursor < len(menu.signals)-1 {
+			menu.cursor++
+		}
+	case "enter":
+		sig := menu.signals[menu.cursor].Signal
+		m.signalMenu = nil
+		m.lastSignal = sig
+		op := "send " + process.SignalName(sig)
+		if menu.batch {
+			return m.confirmBatch(op, sig), nil
+		}
+		status := process.Alive
+		if it := m.findProcess(menu.pid); it != nil {
+			status = it.Status
+		}
+		m.confirm = &confirmPrompt{pid: menu.pid, startTime: menu.startTime, name: menu.name, op: op, sig: sig, status: signalStatus(sig, status)}
+	case "esc", "K":
+		m.signalMenu = nil
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// signalStatus 返回进程在成功收到 sig 后应显示的状态。终止类信号标记为已杀死，
+// STOP/CONT 分别对应暂停与恢复；HUP、USR1 等不改变进程的运行状态，保持 current。
+// Windows 上 sigStop/sigCont 都是 0，因此这里用 if 而不是 switch，避免重复的 case。
+func signalStatus(sig syscall.Signal, current process.Status) process.Status {
+	if sig == syscall.SIGTERM || sig == syscall.SIGKILL || sig == syscall.SIGINT {
+		return process.Killed
+	}
+	if sig != 0 && sig == sigStop {
+		return process.Paused
+	}
+	if sig != 0 && sig == sigCont {
+		return process.Alive
+	}
+	return current
+}
+
+// renderSignalMenuView 渲染信号菜单覆盖层。
+func (m model) renderSignalMenuView() string {
+	menu := m.signalMenu
+	title := confirmTitleStyle.Render("Send Signal")
+	target := fmt.Sprintf("%s (%d)", menu.name, menu.pid)
+	if menu.batch {
+		target = menu.name
+	}
+
+	nameWidth := 0
+	for _, s := range menu.signals {
+		nameWidth = max(nameWidth, len(s.Name))
+	}
+	lines := []string{confirmMessageStyle.Render("Target: " + target)}
+	if m.lastSignal != 0 {
+		lines = append(lines, faintStyle.Render("Last used: "+process.SignalName(m.lastSignal)))
+	}
+	lines = append(lines, "")
+	for i, s := range menu.signals {
+		row := fmt.Sprintf("%-*s %2d  %s", nameWidth, s.Name, int(s.Signal), s.Description)
+		if i == menu.cursor {
+			lines = append(lines, selectedStyle.Render("❯ "+row))
+		} else {
+			lines = append(lines, "  "+row)
+		}
+	}
+	body := confirmPaneStyle.Width(78).Render(strings.Join(lines, "\n"))
+	help := confirmHelpStyle.Render(" up/down: choose • enter: send (after confirmation) • esc: cancel")
+	return docStyle.Render(strings.Join([]string{title, body, help}, "\n"))
+}
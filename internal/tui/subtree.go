@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"syscall"
+
+	"github.com/w31r4/gokill/internal/process"
+)
+
+// subtree.go 实现 T 模式中对整棵子树（`X`）和整个进程组（`G`）的 kill。
+//
+// 只 kill 光标所在节点时，它的 worker 往往会被 init/systemd 收养而继续运行。`X` 从 children
+// 映射收集节点的全部后代，按子进程在前的顺序发送信号；`G` 则用 kill(-pgid) 一次性通知整个
+// 进程组，连列表中尚未出现的新成员也能覆盖。两者都复用批量 kill 的确认对话框、TERM→KILL
+// 策略和逐个进程的结果报告。
+
+// confirmSubtreeKill 为 it 及其全部后代打开确认对话框。已退出的进程不再列出。
+func (m model) confirmSubtreeKill(it *process.Item) model {
+	var targets []batchTarget
+	for _, p := range process.Subtree(it, m.buildChildrenMap()) {
+		if p.Status != process.Exited {
+			targets = append(targets, newBatchTarget(p))
+		}
+	}
+	if len(targets) == 0 {
+		m.notice = "nothing to kill in this subtree"
+		return m
+	}
+	m.confirm = &confirmPrompt{pid: it.Pid, name: it.Executable, op: "kill", sig: syscall.SIGTERM, targets: targets, tree: true}
+	return m
+}
+
+// confirmGroupKill 为 it 所在的进程组打开确认对话框。init 和 gokill 自身所在的进程组会被拒绝。
+func (m model) confirmGroupKill(it *process.Item) model {
+	pgid, err := process.TargetGroup(it.Pid)
+	if err != nil {
+		m.notice = err.Error()
+		return m
+	}
+	var alive []*process.Item
+	for _, p := range m.processes {
+		if p.Status != process.Exited {
+			alive = append(alive, p)
+		}
+	}
+	members := process.GroupMembers(alive, pgid)
+	if len(members) == 0 {
+		members = []*process.Item{it}
+	}
+	targets := make([]batchTarget, 0, len(members))
+	for _, p := range members {
+		targets = append(targets, newBatchTarget(p))
+	}
+	m.confirm = &confirmPrompt{pid: it.Pid, name: it.Executable, op: "kill", sig: syscall.SIGTERM, targets: targets, pgid: pgid}
+	return m
+}
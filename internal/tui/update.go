@@ -1,11 +1,15 @@
 package tui
 
 import (
-	"runtime"
+	"context"
+	"fmt"
+	"slices"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/search"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -15,34 +19,95 @@ import (
 // 当用户执行一个危险操作（如杀死或暂停进程）时，我们会创建一个该结构体的实例，
 // 并将其赋值给 `model.confirm`，从而触发确认视图的显示。
 type confirmPrompt struct {
-	pid           int32          // 目标进程的PID。
-	name          string         // 目标进程的名称。
-	op            string         // 操作类型的可读描述，如 "kill", "pause", "resume", "docker stop"。
-	sig           syscall.Signal // 将要发送给进程的实际系统信号。
-	status        process.Status // 操作成功后，进程应该更新到的新状态。
-	containerName string         // Docker 容器名（非空时使用 docker stop）。
+	pid           int32                   // 目标进程的PID。
+	startTime     time.Time               // 目标进程的启动时间，发送信号前用于确认该 PID 没有被其他进程复用。
+	name          string                  // 目标进程的名称。
+	op            string                  // 操作类型的可读描述，如 "kill", "pause", "resume", "docker stop"。
+	sig           syscall.Signal          // 将要发送给进程的实际系统信号。
+	status        process.Status          // 操作成功后，进程应该更新到的新状态。
+	containerName string                  // Docker 容器名（非空时使用 docker stop）。
+	port          uint32                  // 非零时表示“释放端口”操作：终止该端口的所有监听进程（TERM→KILL）并等待端口关闭。
+	holders       []*process.Item         // 释放端口时对话框中列出的监听进程；执行时端口上出现其他监听者则拒绝操作。
+	targets       []batchTarget           // 非空时表示对多选目标的批量操作，此时忽略 pid/name/containerName。
+	tree          bool                    // targets 是 pid 为根的整个子树（子进程在前），而不是多选。
+	pgid          int                     // 非零时通过 kill(-pgid) 向整个进程组发送信号，targets 为组内已知成员。
+	manager       *process.ManagerCommand // 非 nil 时执行该管理器命令（如 systemctl stop）而不是发送信号。
 }
 
 // Init 是 Bubble Tea 应用生命周期的一部分，在程序首次运行时被调用。
 // 它负责返回一个或多个初始命令（`tea.Cmd`）来启动应用的异步任务。
 func (m model) Init() tea.Cmd {
 	// `tea.Batch` 是一个辅助函数，用于将多个命令合并成一个，以便它们可以并发执行。
-	// 这里我们同时执行两个初始任务：
+	// 这里我们同时执行以下初始任务：
 	// 1. `m.textInput.Focus()`: 使搜索框立即获得焦点，方便用户直接输入。
-	// 2. `getProcesses`: 触发一个异步命令来从系统中获取最新的进程列表。
-	return tea.Batch(m.textInput.Focus(), getProcesses)
+	// 2. `refreshProcesses`: 触发一个异步命令来从系统中获取最新的进程列表。
+	cmds := []tea.Cmd{m.textInput.Focus(), m.refreshProcesses()}
+	// 3. 如果通过 `--details` 启动，则同时加载该进程的详情。
+	if m.showDetails {
+		cmds = append(cmds, m.detailsCmd())
+	}
+	return tea.Batch(cmds...)
 }
 
-// getProcesses 是一个命令（`tea.Cmd`），它封装了获取系统进程列表的耗时操作。
+// refreshProcesses 返回一个命令（`tea.Cmd`），它封装了获取系统进程列表的耗时操作。
 // 在 Bubble Tea 中，任何可能阻塞UI的操作都应该包装在一个命令中。
 // 命令本质上是一个函数，其返回值必须是 `tea.Msg`。Bubble Tea 运行时会
 // 在一个单独的 Goroutine 中执行这个函数，并将返回的消息发送给 `Update` 方法。
-func getProcesses() tea.Msg {
-	procs, warnings, err := process.GetProcesses()
-	if err != nil {
-		return errMsg{err}
+// 已加载过的进程交给 `process.Refresh`，只重新读取会变化的字段。由于命令在
+// 另一个 Goroutine 中运行，这里先在 Update 所在的 Goroutine 上复制一份条目，避免数据竞争。
+func (m model) refreshProcesses() tea.Cmd {
+	prev := make([]*process.Item, 0, len(m.processes))
+	for _, it := range m.processes {
+		clone := *it
+		prev = append(prev, &clone)
+	}
+	return func() tea.Msg {
+		procs, warnings, err := process.Refresh(prev)
+		if err != nil {
+			return processesLoadedMsg{err: err}
+		}
+		return processesLoadedMsg{processes: procs, warnings: warnings}
+	}
+}
+
+// startRefresh 发起一次进程扫描。如果已有扫描在进行，只记下这次请求，
+// 待当前扫描完成后再执行，保证同一时间不会有两次扫描。
+func (m model) startRefresh() (model, tea.Cmd) {
+	if m.refreshing {
+		m.refreshQueued = true
+		return m, nil
+	}
+	m.refreshing = true
+	return m, m.refreshProcesses()
+}
+
+// scheduleAutoRefresh 重新开始自动刷新的计时。之前的计时器会因 tickID 变化而失效，
+// 所以无论调用多少次，同一时间只有一个计时器有效。
+func (m model) scheduleAutoRefresh() (model, tea.Cmd) {
+	m.tickID++
+	if !m.autoRefresh {
+		return m, nil
 	}
-	return processesLoadedMsg{processes: procs, warnings: warnings}
+	id := m.tickID
+	return m, tea.Tick(m.refreshInterval, func(time.Time) tea.Msg {
+		return autoRefreshTickMsg{id: id}
+	})
+}
+
+// toggleAutoRefresh 切换自动刷新。开启时立即计时；关闭时递增 tickID，使待触发的计时器失效。
+func (m model) toggleAutoRefresh() (model, tea.Cmd) {
+	m.autoRefresh = !m.autoRefresh
+	if m.autoRefresh {
+		m.notice = fmt.Sprintf("auto-refresh every %s", m.refreshInterval)
+	} else {
+		m.notice = "auto-refresh off"
+	}
+	return m.scheduleAutoRefresh()
+}
+
+// refreshPaused 报告自动刷新是否应暂缓：确认对话框、信号菜单或详情视图打开时，列表不应在用户眼前变化。
+func (m model) refreshPaused() bool {
+	return m.confirm != nil || m.signalMenu != nil || m.showDetails
 }
 
 // getProcessDetails 是一个命令工厂函数。它接收一个进程PID作为参数，
@@ -50,12 +115,12 @@ func getProcesses() tea.Msg {
 func getProcessDetails(pid int, requestID int64, opts process.DetailsOptions) tea.Cmd {
 	return func() tea.Msg {
 		// 在这个 Goroutine 中执行获取进程详情的耗时操作。
-		details, err := process.GetProcessDetailsWithOptions(pid, opts)
+		details, err := process.GetDetails(pid, opts)
 		if err != nil {
 			// 如果操作失败，返回一个 `errMsg` 消息，将错误传递给 Update 函数。
 			return errMsg{err}
 		}
-		// 如果操作成功，返回一个 `processDetailsMsg` 消息，携带获取到的详情字符串。
+		// 如果操作成功，返回一个 `processDetailsMsg` 消息，携带获取到的详情。
 		return processDetailsMsg{requestID: requestID, details: details}
 	}
 }
@@ -68,12 +133,32 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case processesLoadedMsg:
 		return m.updateProcessesLoaded(msg)
+	case autoRefreshTickMsg:
+		return m.updateAutoRefreshTick(msg)
 	case processDetailsMsg:
 		return m.updateProcessDetails(msg)
+	case detailsTabMsg:
+		return m.updateDetailsTab(msg)
+	case detailsMetricsMsg:
+		return m.updateDetailsMetrics(msg)
+	case detailsLiveTickMsg:
+		return m.updateDetailsLiveTick(msg)
 	case errMsg:
 		return m.updateErr(msg)
 	case signalOKMsg:
 		return m.updateSignalOK(msg)
+	case portFreedMsg:
+		return m.updatePortFreed(msg)
+	case batchDoneMsg:
+		return m.updateBatchDone(msg)
+	case managerLookupMsg:
+		return m.updateManagerLookup(msg)
+	case managerStoppedMsg:
+		return m.updateManagerStopped(msg)
+	case respawnTickMsg:
+		return m.updateRespawnTick()
+	case respawnCauseMsg:
+		return m.updateRespawnCause(msg)
 	case tea.WindowSizeMsg:
 		return m.updateWindowSize(msg), nil
 	case tea.KeyMsg:
@@ -87,15 +172,99 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m.updateDefault(msg)
 }
 
+func (m model) updateAutoRefreshTick(msg autoRefreshTickMsg) (tea.Model, tea.Cmd) {
+	if !m.autoRefresh || msg.id != m.tickID {
+		return m, nil
+	}
+	if m.refreshPaused() || m.refreshing {
+		// 稍后再试；正在进行的扫描完成时也会重新计时。
+		return m.scheduleAutoRefresh()
+	}
+	// 下一次计时在这次扫描完成后开始。
+	return m.startRefresh()
+}
+
 func (m model) updateProcessesLoaded(msg processesLoadedMsg) (tea.Model, tea.Cmd) {
-	m.processes = msg.processes
+	m.refreshing = false
+	var cmds []tea.Cmd
+	if m.refreshQueued {
+		m.refreshQueued = false
+		var cmd tea.Cmd
+		m, cmd = m.startRefresh()
+		cmds = append(cmds, cmd)
+	} else {
+		var cmd tea.Cmd
+		m, cmd = m.scheduleAutoRefresh()
+		cmds = append(cmds, cmd)
+	}
+
+	if msg.err != nil {
+		if m.autoRefresh {
+			// 自动刷新时一次失败的扫描只是暂时的，保留当前列表，不弹出错误覆盖层。
+			m.notice = fmt.Sprintf("refresh failed: %v", msg.err)
+		} else {
+			m.err = msg.err
+		}
+		return m, tea.Batch(cmds...)
+	}
+
+	// 记住光标所在的进程，合并后把光标放回同一个进程上。
+	selected, hasSelected := m.selectedProcess()
+	var selectedKey process.Key
+	if hasSelected {
+		selectedKey = selected.Key()
+	}
+	depLine, hasDepLine := m.depLineAtCursor()
+
+	// 首次加载时直接替换缓存数据（缓存中消失的进程不应显示为“已退出”）；
+	// 之后的刷新则合并快照，保留 Killed/Paused 等标记，并标记已退出的进程。
+	if m.loaded {
+		m.processes = process.Merge(m.processes, msg.processes)
+	} else {
+		m.processes = msg.processes
+		m.loaded = true
+	}
 	m.warnings = msg.warnings
+	m = m.pruneSelection()
+	var respawnCmd tea.Cmd
+	m, respawnCmd = m.detectRespawns()
+	cmds = append(cmds, respawnCmd)
 
 	m.filtered = m.filterProcesses(m.textInput.Value())
-	return m, func() tea.Msg {
-		_ = process.Save(m.processes)
-		return nil
+	m.cursor = clampIndex(m.cursor, len(m.filtered))
+	if hasSelected {
+		for i, it := range m.filtered {
+			if it.Key() == selectedKey {
+				m.cursor = i
+				break
+			}
+		}
+	}
+	if m.dep.mode {
+		if hasDepLine {
+			for i, ln := range applyDepFilters(m, buildDepLines(m)) {
+				if ln.pid == depLine.pid && ln.pid != 0 {
+					m.dep.cursor = i
+					break
+				}
+			}
+		}
+		m = m.clampDepCursor()
+	}
+
+	// 缓存只保存仍在运行的进程；复制一份，避免与后续合并产生数据竞争。
+	toSave := make([]*process.Item, 0, len(m.processes))
+	for _, it := range m.processes {
+		if it.Status != process.Exited {
+			clone := *it
+			toSave = append(toSave, &clone)
+		}
 	}
+	cmds = append(cmds, func() tea.Msg {
+		_ = process.Save(toSave)
+		return nil
+	})
+	return m, tea.Batch(cmds...)
 }
 
 func (m model) updateProcessDetails(msg processDetailsMsg) (tea.Model, tea.Cmd) {
@@ -104,28 +273,14 @@ func (m model) updateProcessDetails(msg processDetailsMsg) (tea.Model, tea.Cmd)
 	}
 
 	m.processDetails = msg.details
-
-	vpHFrame, _ := m.detailsViewport.Style.GetFrameSize()
-	contentWidth := m.detailsViewport.Width - vpHFrame
-	formattedDetails := formatProcessDetails(m.processDetails, contentWidth)
-
-	lines := strings.Split(formattedDetails, "\n")
-	contentHeight := len(lines)
-	if contentHeight <= 0 {
-		contentHeight = 1
-	}
-	maxHeight := m.detailsViewport.Height
-	if maxHeight <= 0 {
-		maxHeight = contentHeight
+	if m.detailsStartTime.IsZero() && msg.details != nil {
+		// 通过 --details 打开时列表尚未加载，以详情中的启动时间为准。
+		m.detailsStartTime = msg.details.StartTime
 	}
-	if contentHeight > maxHeight {
-		m.detailsViewport.Height = maxHeight
-	} else {
-		m.detailsViewport.Height = contentHeight
+	if m.detailsTab == tabOverview {
+		m.setDetailsContent()
+		m.detailsViewport.GotoTop()
 	}
-
-	m.detailsViewport.SetContent(formattedDetails)
-	m.detailsViewport.GotoTop()
 	return m, nil
 }
 
@@ -140,21 +295,48 @@ func (m model) updateSignalOK(msg signalOKMsg) (tea.Model, tea.Cmd) {
 	for _, it := range m.processes {
 		if int(it.Pid) == msg.pid {
 			it.Status = msg.status
+			if msg.status == process.Killed {
+				return m.trackDeparted([]*process.Item{it}, time.Now())
+			}
 			break
 		}
 	}
 	return m, nil
 }
 
+// updatePortFreed 展示释放端口的结果，并刷新进程列表以反映被终止或重新拉起的进程。
+func (m model) updatePortFreed(msg portFreedMsg) (tea.Model, tea.Cmd) {
+	m.notice = msg.result.Summary()
+	var killed []*process.Item
+	for _, h := range msg.result.Holders {
+		if h.Err != nil {
+			continue
+		}
+		killed = append(killed, h.Item)
+		for _, it := range m.processes {
+			if it.Pid == h.Item.Pid {
+				it.Status = process.Killed
+			}
+		}
+	}
+	m.filtered = m.filterProcesses(m.textInput.Value())
+	m.cursor = clampIndex(m.cursor, len(m.filtered))
+	m, watchCmd := m.trackDeparted(killed, time.Now().Add(-msg.result.Elapsed))
+	m, refreshCmd := m.startRefresh()
+	return m, tea.Batch(refreshCmd, watchCmd)
+}
+
 func (m model) updateWindowSize(msg tea.WindowSizeMsg) model {
 	headerHeight := lipgloss.Height(detailTitleStyle.Render("Process Details"))
 	footerHeight := lipgloss.Height(detailHelpStyle.Render(" esc: back to list • up/down/pgup/pgdn: scroll"))
+	tabBarHeight := lipgloss.Height(m.renderDetailsTabBar())
+	metricsHeight := 1 // renderDetailsMetrics 截断到 viewport 宽度，始终占一行。
 
 	docHFrame, docVFrame := docStyle.GetFrameSize()
 	paneHFrame, paneVFrame := detailPaneStyle.GetFrameSize()
 
 	viewportWidth := msg.Width - docHFrame - paneHFrame
-	viewportHeight := msg.Height - docVFrame - paneVFrame - headerHeight - footerHeight
+	viewportHeight := msg.Height - docVFrame - paneVFrame - headerHeight - metricsHeight - tabBarHeight - footerHeight
 
 	if viewportWidth < 0 {
 		viewportWidth = 0
@@ -165,6 +347,13 @@ func (m model) updateWindowSize(msg tea.WindowSizeMsg) model {
 
 	m.detailsViewport.Width = viewportWidth
 	m.detailsViewport.Height = viewportHeight
+	m.detailsMaxHeight = viewportHeight
+	m.confirmListHeight = max(msg.Height-docVFrame-confirmChromeHeight, 3)
+	m = m.scrollConfirm(0)
+	if m.showDetails {
+		// 按新宽度重新排版当前标签页。
+		m.setDetailsContent()
+	}
 	return m
 }
 
@@ -192,12 +381,13 @@ func sendSignal(pid int, sig syscall.Signal) tea.Cmd {
 // 它不仅发送信号，而且在成功后会返回一条 `signalOKMsg` 消息。
 // `Update` 函数接收到这条消息后，才会安全地更新UI中进程的状态。
 // 这种方式确保了UI状态的变更总是基于已确认的成功操作。
-func sendSignalWithStatus(pid int, sig syscall.Signal, status process.Status) tea.Cmd {
+// startTime 是收集进程时记录的启动时间：若该 PID 已属于另一个进程，信号会被拒绝。
+func sendSignalWithStatus(pid int32, startTime time.Time, sig syscall.Signal, status process.Status) tea.Cmd {
 	return func() tea.Msg {
-		if err := process.SendSignal(pid, sig); err != nil {
+		if err := process.SignalItem(&process.Item{Pid: pid, StartTime: startTime}, sig); err != nil {
 			return errMsg{err}
 		}
-		return signalOKMsg{pid: pid, status: status}
+		return signalOKMsg{pid: int(pid), status: status}
 	}
 }
 
@@ -212,6 +402,19 @@ func stopContainer(pid int, containerName string) tea.Cmd {
 	}
 }
 
+// freePort 是释放端口的命令工厂。它在后台执行 TERM→KILL 升级并轮询端口，
+// 完成后返回 `portFreedMsg`，因此可能需要数秒。holders 是用户确认过的监听进程：
+// 若重新扫描时端口被其他进程占用，则不发送任何信号并报告错误。
+func freePort(port uint32, holders []*process.Item) tea.Cmd {
+	return func() tea.Msg {
+		res, err := process.FreePort(context.Background(), port, process.FreePortOptions{Wait: true, Holders: holders})
+		if err != nil {
+			return errMsg{err}
+		}
+		return portFreedMsg{result: res}
+	}
+}
+
 // updateKeyMsg 是一个关键的调度函数，它根据当前的UI模式（如帮助、确认、依赖树等）
 // 将接收到的 `tea.KeyMsg` 分发给相应的子处理函数。
 // 这种分层处理使得每个模式的按键逻辑可以被独立管理，大大降低了 `Update` 函数的复杂性。
@@ -222,7 +425,7 @@ func stopContainer(pid int, containerName string) tea.Cmd {
 //   - `bool`: `true` 表示按键已被当前模式完全处理；`false` 表示需要交由后续的默认逻辑处理。
 func (m model) updateKeyMsg(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 	// 模式的检查顺序需要与 View 的渲染优先级保持一致，避免“界面显示 A，但按键处理走 B”的状态错位。
-	// Priority (high → low): error, confirm, help, details, dep-mode, search, main list.
+	// Priority (high → low): error, confirm, signal menu, help, details, dep-mode, search, main list.
 	if m.err != nil {
 		newModel, cmd := m.updateErrorKey(msg)
 		return newModel, cmd, true
@@ -231,6 +434,10 @@ func (m model) updateKeyMsg(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 		newModel, cmd := m.updateConfirmKey(msg)
 		return newModel, cmd, true
 	}
+	if m.signalMenu != nil {
+		newModel, cmd := m.updateSignalMenuKey(msg)
+		return newModel, cmd, true
+	}
 	if m.helpOpen {
 		newModel, cmd := m.updateHelpKey(msg)
 		return newModel, cmd, true
@@ -272,14 +479,44 @@ func (m model) updateConfirmKey(msg tea.KeyMsg) (model, tea.Cmd) {
 	case "y", "enter":
 		op := *m.confirm // 复制确认操作的上下文
 		m.confirm = nil  // 清除确认状态，关闭对话框
+		m.confirmScroll = 0
+		if len(op.targets) > 0 {
+			if !op.tree && op.pgid == 0 {
+				m = m.clearSelection()
+			}
+			return m.startBatch(op.op, op.targets, op.sig, op.pgid)
+		}
+		if op.port != 0 {
+			m.notice = fmt.Sprintf("Freeing port %d…", op.port)
+			return m, freePort(op.port, op.holders)
+		}
+		if op.manager != nil {
+			m.notice = fmt.Sprintf("running `%s`…", op.manager)
+			return m, runManagerStop(op.pid, *op.manager)
+		}
 		// 如果是 Docker 容器，使用 docker stop；否则发送系统信号。
 		if op.containerName != "" {
 			return m, stopContainer(int(op.pid), op.containerName)
 		}
-		return m, sendSignalWithStatus(int(op.pid), op.sig, op.status)
+		// kill 通过 TERM→KILL 策略执行，并确认进程是否真正退出。
+		// 按确认时的身份查找：PID 已被复用时找不到条目，交给下面的单次信号路径拒绝。
+		key := (&process.Item{Pid: op.pid, StartTime: op.startTime}).Key()
+		if it := m.findByKey(key); it != nil && op.op == "kill" {
+			return m.startBatch(op.op, []batchTarget{newBatchTarget(it)}, op.sig, 0)
+		}
+		return m, sendSignalWithStatus(op.pid, op.startTime, op.sig, op.status)
 	case "n", "esc":
 		m.confirm = nil // 取消操作，关闭对话框
+		m.confirmScroll = 0
 		return m, nil
+	case "up", "k":
+		return m.scrollConfirm(-1), nil
+	case "down", "j":
+		return m.scrollConfirm(1), nil
+	case "pgup":
+		return m.scrollConfirm(-m.confirmListHeight), nil
+	case "pgdown":
+		return m.scrollConfirm(m.confirmListHeight), nil
 	case "ctrl+c", "q":
 		return m, tea.Quit // 退出程序
 	}
@@ -292,6 +529,8 @@ func (m model) updateDepModeKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 	if m.textInput.Focused() {
 		return m.updateDepModeSearchKey(msg)
 	}
+	// 与主列表一致，任意按键都会清除上一次操作的提示。
+	m.notice = ""
 	if newModel, cmd, handled := m.handleDepModeGlobalKey(msg); handled {
 		return newModel, cmd, true
 	}
@@ -332,7 +571,11 @@ func (m model) handleDepModeGlobalKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 		m.helpOpen = true
 		return m, nil, true
 	case "ctrl+r":
-		return m, getProcesses, true
+		newModel, cmd := m.startRefresh()
+		return newModel, cmd, true
+	case "R":
+		newModel, cmd := m.toggleAutoRefresh()
+		return newModel, cmd, true
 	}
 	return m, nil, false
 }
@@ -376,28 +619,73 @@ func (m model) handleDepModeActionKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 			return newModel, cmd, true
 		}
 		return m, nil, true
+	case "K":
+		if ln, ok := m.depLineAtCursor(); ok {
+			m = m.openSignalMenu(m.findProcess(ln.pid))
+		}
+		return m, nil, true
+	case "M":
+		if ln, ok := m.depLineAtCursor(); ok {
+			newModel, cmd := m.lookupManagerStop(m.findProcess(ln.pid))
+			return newModel, cmd, true
+		}
+		return m, nil, true
+	case "m":
+		if ln, ok := m.depLineAtCursor(); ok && ln.pid != 0 {
+			if it := m.findProcess(ln.pid); it != nil {
+				m = m.toggleSelected(it)
+			}
+		}
+		return m, nil, true
+	case "A":
+		return m.selectAll(m.visibleDepItems()), nil, true
+	case "I":
+		return m.invertSelection(m.visibleDepItems()), nil, true
 	case "x":
+		if len(m.selected) > 0 {
+			return m.confirmBatch("kill", syscall.SIGTERM), nil, true
+		}
 		if ln, ok := m.depLineAtCursor(); ok {
 			if it := m.findProcess(ln.pid); it != nil {
 				if it.ContainerName != "" {
 					m.confirm = &confirmPrompt{pid: ln.pid, name: it.ContainerName, op: "docker stop", sig: syscall.SIGTERM, status: process.Killed, containerName: it.ContainerName}
 				} else {
-					m.confirm = &confirmPrompt{pid: ln.pid, name: it.Executable, op: "kill", sig: syscall.SIGTERM, status: process.Killed}
+					m.confirm = &confirmPrompt{pid: ln.pid, startTime: it.StartTime, name: it.Executable, op: "kill", sig: syscall.SIGTERM, status: process.Killed}
 				}
 			}
 		}
 		return m, nil, true
+	case "X":
+		if ln, ok := m.depLineAtCursor(); ok {
+			if it := m.findProcess(ln.pid); it != nil {
+				m = m.confirmSubtreeKill(it)
+			}
+		}
+		return m, nil, true
+	case "G":
+		if ln, ok := m.depLineAtCursor(); ok {
+			if it := m.findProcess(ln.pid); it != nil {
+				m = m.confirmGroupKill(it)
+			}
+		}
+		return m, nil, true
 	case "p":
+		if len(m.selected) > 0 {
+			return m.confirmBatch("pause", sigStop), nil, true
+		}
 		if ln, ok := m.depLineAtCursor(); ok {
 			if it := m.findProcess(ln.pid); it != nil {
-				m.confirm = &confirmPrompt{pid: ln.pid, name: it.Executable, op: "pause", sig: sigStop, status: process.Paused}
+				m.confirm = &confirmPrompt{pid: ln.pid, startTime: it.StartTime, name: it.Executable, op: "pause", sig: sigStop, status: process.Paused}
 			}
 		}
 		return m, nil, true
 	case "r":
+		if len(m.selected) > 0 {
+			return m.confirmBatch("resume", sigCont), nil, true
+		}
 		if ln, ok := m.depLineAtCursor(); ok {
 			if it := m.findProcess(ln.pid); it != nil && it.Status == process.Paused {
-				m.confirm = &confirmPrompt{pid: ln.pid, name: it.Executable, op: "resume", sig: sigCont, status: process.Alive}
+				m.confirm = &confirmPrompt{pid: ln.pid, startTime: it.StartTime, name: it.Executable, op: "resume", sig: sigCont, status: process.Alive}
 			}
 		}
 		return m, nil, true
@@ -543,30 +831,38 @@ func (m model) updateDetailsKey(msg tea.KeyMsg) (model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
 		m.showDetails = false
-		m.processDetails = "" // 清空详情内容，以便下次重新加载。
+		m.processDetails = nil // 清空详情内容，以便下次重新加载。
 		m.detailsPID = 0
 		m.detailsVerbose = false
-		m.detailsShowEnv = false
 		m.detailsRevealSecrets = false
+		m.detailsTab = tabOverview
+		m.detailsTabs = nil
+		m = m.resetDetailsLive()
 	case "?":
 		m.helpOpen = true
 	case "ctrl+c":
 		return m, tea.Quit
+	case "tab":
+		return m.selectDetailsTab(m.detailsTab.next(1))
+	case "shift+tab":
+		return m.selectDetailsTab(m.detailsTab.next(-1))
+	case "1", "2", "3", "4", "5", "6", "7":
+		return m.selectDetailsTab(detailsTab(msg.String()[0] - '1'))
 	case "v":
 		m.detailsVerbose = !m.detailsVerbose
 		return m.reloadProcessDetails()
+	case "L":
+		return m.toggleDetailsLive()
 	case "e":
-		m.detailsShowEnv = !m.detailsShowEnv
-		if !m.detailsShowEnv {
-			m.detailsRevealSecrets = false
-		}
-		return m.reloadProcessDetails()
+		return m.selectDetailsTab(tabEnv)
 	case "s":
-		// Secret reveal only applies when env is visible.
-		if m.detailsShowEnv {
-			m.detailsRevealSecrets = !m.detailsRevealSecrets
-			return m.reloadProcessDetails()
+		// 切换后丢弃已加载的 Env，下次查看（或当前正在查看）时按新设置重新读取。
+		m.detailsRevealSecrets = !m.detailsRevealSecrets
+		delete(m.detailsTabs, tabEnv)
+		if m.detailsTab == tabEnv {
+			return m.selectDetailsTab(tabEnv)
 		}
+		return m, nil
 	}
 	// 将按键转发给 viewport
 	var cmd tea.Cmd
@@ -591,6 +887,8 @@ func (m model) updateSearchKey(msg tea.KeyMsg) (model, tea.Cmd) {
 // updateMainListKey 处理在主进程列表视图中的默认按键事件。
 // 这是当所有其他特定模式（如帮助、确认、依赖树等）都未激活时的最终处理器。
 func (m model) updateMainListKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	// 任意按键都会清除上一次操作的提示。
+	m.notice = ""
 	if newModel, cmd, handled := m.handleMainListGlobalKey(msg); handled {
 		return newModel, cmd, true
 	}
@@ -612,7 +910,11 @@ func (m model) handleMainListGlobalKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 	case "ctrl+c", "q":
 		return m, tea.Quit, true
 	case "ctrl+r":
-		return m, getProcesses, true
+		newModel, cmd := m.startRefresh()
+		return newModel, cmd, true
+	case "R":
+		newModel, cmd := m.toggleAutoRefresh()
+		return newModel, cmd, true
 	}
 	return m, nil, false
 }
@@ -620,6 +922,10 @@ func (m model) handleMainListGlobalKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 func (m model) handleMainListViewKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 	switch msg.String() {
 	case "esc":
+		if len(m.selected) > 0 {
+			// esc 先清空选择，再退出 ports-only。
+			return m.clearSelection(), nil, true
+		}
 		if !m.portsOnly {
 			return m, nil, false
 		}
@@ -643,10 +949,42 @@ func (m model) handleMainListViewKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 			m = m.enterDepMode(p.Pid)
 		}
 		return m, nil, true
+	case "s":
+		m.sortKey = m.sortKey.Next()
+		m.sortDesc = m.sortKey.DefaultDescending()
+		return m.refilterKeepingSelection(), nil, true
+	case "a":
+		m.absoluteStart = !m.absoluteStart
+		return m, nil, true
+	case "c":
+		m.showCmdline = !m.showCmdline
+		return m, nil, true
+	case "S":
+		if m.sortKey == search.SortNone {
+			return m, nil, true
+		}
+		m.sortDesc = !m.sortDesc
+		return m.refilterKeepingSelection(), nil, true
 	}
 	return m, nil, false
 }
 
+// refilterKeepingSelection 重新过滤/排序主列表，并让光标停留在原来选中的进程上。
+func (m model) refilterKeepingSelection() model {
+	selected, ok := m.selectedProcess()
+	m.filtered = m.filterProcesses(m.textInput.Value())
+	m.cursor = clampIndex(m.cursor, len(m.filtered))
+	if ok {
+		for i, it := range m.filtered {
+			if it == selected {
+				m.cursor = i
+				break
+			}
+		}
+	}
+	return m
+}
+
 func (m model) handleMainListNavKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 	switch msg.String() {
 	case "up", "k":
@@ -665,24 +1003,54 @@ func (m model) handleMainListNavKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 
 func (m model) handleMainListActionKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 	switch msg.String() {
+	case " ":
+		if p, ok := m.selectedProcess(); ok {
+			m = m.toggleSelected(p)
+			// 标记后下移一行，便于连续标记。
+			if m.cursor < len(m.filtered)-1 {
+				m.cursor++
+			}
+		}
+		return m, nil, true
+	case "K":
+		p, _ := m.selectedProcess()
+		return m.openSignalMenu(p), nil, true
+	case "M":
+		p, _ := m.selectedProcess()
+		newModel, cmd := m.lookupManagerStop(p)
+		return newModel, cmd, true
+	case "A":
+		return m.selectAll(m.filtered), nil, true
+	case "I":
+		return m.invertSelection(m.filtered), nil, true
 	case "enter":
+		if len(m.selected) > 0 {
+			return m.confirmBatch("kill", syscall.SIGTERM), nil, true
+		}
 		if p, ok := m.selectedProcess(); ok {
 			if p.ContainerName != "" {
 				// Docker containers go through confirm dialog since docker stop is a heavier operation.
 				m.confirm = &confirmPrompt{pid: p.Pid, name: p.ContainerName, op: "docker stop", sig: syscall.SIGTERM, status: process.Killed, containerName: p.ContainerName}
 				return m, nil, true
 			}
-			return m, sendSignalWithStatus(int(p.Pid), syscall.SIGTERM, process.Killed), true
+			newModel, cmd := m.startBatch("kill", []batchTarget{newBatchTarget(p)}, syscall.SIGTERM, 0)
+			return newModel, cmd, true
 		}
 		return m, nil, false
 	case "p":
+		if len(m.selected) > 0 {
+			return m.confirmBatch("pause", sigStop), nil, true
+		}
 		if p, ok := m.selectedProcess(); ok {
-			return m, sendSignalWithStatus(int(p.Pid), sigStop, process.Paused), true
+			return m, sendSignalWithStatus(p.Pid, p.StartTime, sigStop, process.Paused), true
 		}
 		return m, nil, false
 	case "r":
+		if len(m.selected) > 0 {
+			return m.confirmBatch("resume", sigCont), nil, true
+		}
 		if p, ok := m.selectedProcess(); ok && p.Status == process.Paused {
-			return m, sendSignalWithStatus(int(p.Pid), sigCont, process.Alive), true
+			return m, sendSignalWithStatus(p.Pid, p.StartTime, sigCont, process.Alive), true
 		}
 		return m, nil, false
 	case "i":
@@ -691,10 +1059,35 @@ func (m model) handleMainListActionKey(msg tea.KeyMsg) (model, tea.Cmd, bool) {
 			return newModel, cmd, true
 		}
 		return m, nil, false
+	case "F":
+		if p, ok := m.selectedProcess(); ok {
+			if len(p.Ports) == 0 {
+				m.notice = fmt.Sprintf("%s (%d) is not listening on any port", p.Executable, p.Pid)
+				return m, nil, true
+			}
+			// 释放所选进程的最小端口；端口上的其他监听进程也会一并被终止。
+			port := p.Ports[0]
+			m.confirm = &confirmPrompt{pid: p.Pid, name: p.Executable, op: fmt.Sprintf("free port %d", port), sig: syscall.SIGTERM, status: process.Killed, port: port, holders: m.portHolders(port)}
+			return m, nil, true
+		}
+		return m, nil, false
 	}
 	return m, nil, false
 }
 
+// portHolders 返回列表中监听 port 的进程副本，作为“释放端口”确认时的监听者集合。
+// 复制条目是为了让后台命令不读取会被刷新修改的条目。
+func (m model) portHolders(port uint32) []*process.Item {
+	var out []*process.Item
+	for _, it := range m.processes {
+		if it.Status == process.Exited || !slices.Contains(it.Ports, port) {
+			continue
+		}
+		out = append(out, &process.Item{Pid: it.Pid, Executable: it.Executable, StartTime: it.StartTime, ContainerName: it.ContainerName})
+	}
+	return out
+}
+
 func (m model) selectedProcess() (*process.Item, bool) {
 	if m.cursor < 0 || m.cursor >= len(m.filtered) {
 		return nil, false
@@ -715,11 +1108,16 @@ func (m model) enterDepMode(pid int32) model {
 
 func (m model) openProcessDetails(pid int32) (model, tea.Cmd) {
 	m.showDetails = true
-	m.processDetails = ""
+	m.processDetails = nil
 	m.detailsPID = pid
-	m.detailsVerbose = false
-	m.detailsShowEnv = runtime.GOOS == "linux"
+	m.detailsStartTime = time.Time{}
+	if it := m.findProcess(pid); it != nil {
+		m.detailsStartTime = it.StartTime
+	}
+	m.detailsVerbose = m.verboseByDefault
 	m.detailsRevealSecrets = false
+	m.detailsTab = tabOverview
+	m = m.resetDetailsLive()
 	m.detailsViewport.SetContent("Loading...")
 	return m.reloadProcessDetails()
 }
@@ -729,17 +1127,29 @@ func (m model) reloadProcessDetails() (model, tea.Cmd) {
 		return m, nil
 	}
 
+	// 新的 requestID 会丢弃所有在途响应，因此已缓存的标签页也一并重新加载。
 	m.detailsRequestID++
+	m.detailsTabs = make(map[detailsTab]detailsTabResult)
 	m.detailsViewport.SetContent("Loading...")
 	m.detailsViewport.GotoTop()
-
-	opts := process.DetailsOptions{
-		Verbose:          m.detailsVerbose,
-		ShowEnv:          m.detailsShowEnv,
-		RevealEnvSecrets: m.detailsRevealSecrets,
+	cmds := []tea.Cmd{m.detailsCmd()}
+	if m.detailsTab != tabOverview {
+		var tabCmd tea.Cmd
+		m, tabCmd = m.selectDetailsTab(m.detailsTab)
+		cmds = append(cmds, tabCmd)
 	}
+	if m.detailsLive {
+		// 在途采样携带旧 requestID 会被丢弃，按新请求重新开始采样链。
+		cmds = append(cmds, sampleDetailsMetrics(m.detailsSampler, int(m.detailsPID), m.detailsStartTime, m.detailsRequestID, m.detailsLiveID))
+	}
+	return m, tea.Batch(cmds...)
+}
 
-	return m, getProcessDetails(int(m.detailsPID), m.detailsRequestID, opts)
+// detailsCmd builds the fetch command for the current details request.
+func (m model) detailsCmd() tea.Cmd {
+	// 环境变量在 Env 标签页中单独完整加载，Overview 不再收集。
+	opts := process.DetailsOptions{Verbose: m.detailsVerbose}
+	return getProcessDetails(int(m.detailsPID), m.detailsRequestID, opts)
 }
 
 // max 是一个简单的辅助函数，返回两个整数中的较大者。
@@ -1,10 +1,13 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/w31r4/gokill/internal/process"
+	"github.com/w31r4/gokill/internal/search"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -23,12 +26,22 @@ var (
 	killingStyle = lipgloss.NewStyle().Strikethrough(true).Foreground(lipgloss.Color("9"))
 	// pausedStyle 定义了被标记为“已暂停”的进程的样式，使用黄色进行提示。
 	pausedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+	// exitedStyle 定义了在最近一次刷新中已退出的进程的样式，使用淡色和删除线，下一次刷新后该行会消失。
+	exitedStyle = lipgloss.NewStyle().Faint(true).Strikethrough(true)
+	// selectedMarkStyle 定义了多选标记（●）的样式。
+	selectedMarkStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
+	// zombieStyle 定义了 kill 后成为僵尸（尚未被父进程回收）的进程的样式。
+	zombieStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("133")).Strikethrough(true)
+	// survivedStyle 定义了 kill 之后仍然存活的进程的样式，用醒目的红色提示 kill 没有生效。
+	survivedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
+	// respawnedStyle 定义了在 kill 之后被重新拉起的进程的样式，用橙色提示它“又回来了”。
+	respawnedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Bold(true)
 	// listeningStyle 定义了正在监听端口的进程的样式，同样使用黄色以引起注意。
 	listeningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
 	// paneStyle 是所有面板（如进程列表、端口信息）的基础样式，定义了圆角边框和内边距。
 	paneStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
 	// processPaneStyle 是左侧进程列表面板的专用样式，继承自 paneStyle 并设置了宽度和边框颜色。
-	processPaneStyle = paneStyle.Copy().Width(60).BorderForeground(lipgloss.Color("62"))
+	processPaneStyle = paneStyle.Copy().Width(78).BorderForeground(lipgloss.Color("62"))
 	// portPaneStyle 是右侧端口信息面板的专用样式，同样继承自 paneStyle 并进行了定制。
 	portPaneStyle = paneStyle.Copy().Width(16).BorderForeground(lipgloss.Color("220")).Align(lipgloss.Left)
 	// detailTitleStyle 定义了详情视图的标题样式。
@@ -37,6 +50,8 @@ var (
 	detailPaneStyle = paneStyle.Copy().BorderForeground(lipgloss.Color("63")).Padding(1, 2)
 	// detailLabelStyle 定义了详情视图中标签（如 "PID:", "User:"）的样式，使其右对齐并加粗。
 	detailLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true).Width(12).Align(lipgloss.Right)
+	// detailTableHeaderStyle 用于详情标签页中表格的表头，颜色与标签一致但不固定宽度。
+	detailTableHeaderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
 	// detailValueStyle 定义了详情视图中值的样式。
 	detailValueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("255"))
 	// detailMetricStyle 用于详情中的关键指标高亮（CPU/MEM 等）。
@@ -50,6 +65,8 @@ var (
 	errorMessageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
 	// warningStyle 定义了警告信息的样式（红色，加粗）。
 	warningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
+	// noticeStyle 定义了后台操作结果提示（如释放端口）的样式（金色）。
+	noticeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("178"))
 	// confirm...Style 定义了确认对话框覆盖层的各种样式。
 	confirmTitleStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("178")).Bold(true)
 	confirmPaneStyle    = paneStyle.Copy().BorderForeground(lipgloss.Color("178")).Width(70).Padding(1, 2)
@@ -66,6 +83,10 @@ var (
 	pidStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("33"))
 	// timeStyle for Start Time (Faint/Gray)
 	timeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	// argsStyle for the command line column (Faint)
+	argsStyle = lipgloss.NewStyle().Faint(true)
+	// usageStyle for CPU%, RSS and thread count (Cyan)
+	usageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("80"))
 	// commandStyle for Command Name (White, Bold)
 	commandStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("255")).Bold(true)
 
@@ -99,6 +120,9 @@ func (m model) View() string {
 	if m.confirm != nil {
 		return m.renderConfirmView()
 	}
+	if m.signalMenu != nil {
+		return m.renderSignalMenuView()
+	}
 	if m.helpOpen {
 		return m.renderHelpView()
 	}
@@ -141,6 +165,11 @@ func (m model) View() string {
 // 这是一个全屏的覆盖视图。
 func (m model) renderDetailsView() string {
 	title := detailTitleStyle.Render("Process Details")
+	if m.detailsLive {
+		title += noticeStyle.Render(fmt.Sprintf("  ● live %s", detailsLiveInterval))
+	}
+	metrics := m.renderDetailsMetrics(m.detailsViewport.Width)
+	tabs := m.renderDetailsTabBar()
 
 	// 渲染 viewport 内容
 	pane := detailPaneStyle.Render(m.detailsViewport.View())
@@ -149,18 +178,18 @@ func (m model) renderDetailsView() string {
 	if m.detailsVerbose {
 		verbose = "on"
 	}
-	env := "off"
-	if m.detailsShowEnv {
-		env = "on"
-	}
 	secrets := "off"
 	if m.detailsRevealSecrets {
 		secrets = "on"
 	}
+	live := "off"
+	if m.detailsLive {
+		live = "on"
+	}
 
-	helpText := " esc: back • ?: help • scroll: up/down/pgup/pgdn • v:verbose[" + verbose + "] • e:env[" + env + "] • s:secrets[" + secrets + "]"
+	helpText := " esc: back • ?: help • tab/1-7: switch tab • scroll: up/down/pgup/pgdn • L:live[" + live + "] • v:verbose[" + verbose + "] • s:secrets[" + secrets + "]"
 	help := detailHelpStyle.Render(helpText)
-	content := lipgloss.JoinVertical(lipgloss.Left, title, pane, help)
+	content := lipgloss.JoinVertical(lipgloss.Left, title, metrics, tabs, pane, help)
 	return docStyle.Render(content)
 }
 
@@ -176,6 +205,26 @@ func (m model) renderHeader() string {
 	if m.portsOnly {
 		mode = faintStyle.Render(" [ports-only]")
 	}
+	if m.userFilter != "" {
+		mode += faintStyle.Render(fmt.Sprintf(" [user: %s]", m.userFilter))
+	}
+	if m.sortKey != search.SortNone {
+		dir := "↑"
+		if m.sortDesc {
+			dir = "↓"
+		}
+		mode += faintStyle.Render(fmt.Sprintf(" [sort: %s %s]", m.sortKey, dir))
+	}
+	if m.autoRefresh {
+		mode += faintStyle.Render(fmt.Sprintf(" [auto: %s]", m.refreshInterval))
+	}
+	if n := len(m.selected); n > 0 {
+		mode += selectedMarkStyle.Render(fmt.Sprintf(" [%d selected]", n))
+	}
+	// 查询中无效的结构化条件会被忽略，这里提示用户（例如输入到一半的 `cpu>`）。
+	if _, err := search.ParseQuery(m.textInput.Value()); err != nil {
+		mode += noticeStyle.Render(fmt.Sprintf(" [ignored %v]", err))
+	}
 	// Join title, count, warnings, mode and the text input view.
 	return fmt.Sprintf("Search processes/ports %s%s%s: %s", faintStyle.Render(count), warnings, mode, m.textInput.View())
 }
@@ -188,7 +237,10 @@ func (m model) renderFooter() string {
 		help.WriteString(faintStyle.Render(" enter/esc to exit search"))
 	} else {
 		// 在非搜索状态下，显示一个精简的核心操作指南。
-		help.WriteString(faintStyle.Render("?: help • /: search • P: ports • T: tree • i: info • enter: kill • p: pause • r: resume • q: quit"))
+		help.WriteString(faintStyle.Render("?: help • /: search • P: ports • s: sort • T: tree • i: info • space: mark • enter: kill • K: signal • F: free port • p: pause • r: resume • q: quit"))
+	}
+	if m.notice != "" {
+		return noticeStyle.Render(m.notice) + "\n" + help.String()
 	}
 	return help.String()
 }
@@ -237,14 +289,18 @@ func (m model) renderDependencyContent(root *process.Item, lines []depLine, star
 	for i := start; i < end; i++ {
 		ln := lines[i]
 		lineText := m.depLineText(ln, childrenMap)
+		mark := m.selectionMark(m.findProcess(ln.pid))
 		if i == m.dep.cursor {
-			fmt.Fprintln(&b, selectedStyle.Render("❯ "+lineText))
+			fmt.Fprintln(&b, selectedStyle.Render("❯ ")+mark+lineText)
 		} else {
-			fmt.Fprintln(&b, "  "+lineText)
+			fmt.Fprintln(&b, "  "+mark+lineText)
 		}
 	}
 
 	fmt.Fprintln(&b, "")
+	if m.notice != "" {
+		fmt.Fprintln(&b, noticeStyle.Render(m.notice))
+	}
 	fmt.Fprintln(&b, m.depHelpLine())
 	return strings.TrimRight(b.String(), "\n")
 }
@@ -274,6 +330,16 @@ func (m model) depLineText(ln depLine, childrenMap map[int32][]*process.Item) st
 		lineText = killingStyle.Render(lineText)
 	case process.Paused:
 		lineText = pausedStyle.Render(lineText)
+	case process.Exited:
+		lineText = exitedStyle.Render(lineText)
+	case process.Zombie:
+		lineText = zombieStyle.Render(lineText)
+	case process.Survived:
+		lineText = survivedStyle.Render(lineText)
+	default:
+		if m.isRespawned(it) {
+			lineText = respawnedStyle.Render(lineText)
+		}
 	}
 	if m.depHasHiddenChildren(ln, childrenMap) {
 		lineText += faintStyle.Render(" +")
@@ -294,7 +360,7 @@ func (m model) depHasHiddenChildren(ln depLine, childrenMap map[int32][]*process
 }
 
 func (m model) depHelpLine() string {
-	help := "esc: back • /: filter • a: ancestors • s: alive • l: listen • u: up • enter/o: root • i: info"
+	help := "esc: back • /: filter • a: ancestors • s: alive • l: listen • u: up • enter/o: root • i: info • m: mark • K: signal"
 	if badges := m.depFilterBadges(); len(badges) > 0 {
 		help += " [" + strings.Join(badges, ", ") + "]"
 	}
@@ -305,6 +371,9 @@ func (m model) depFilterBadges() []string {
 	var badges []string
 	if m.textInput.Value() != "" {
 		badges = append(badges, fmt.Sprintf("filter: %q", m.textInput.Value()))
+		if _, err := search.ParseQuery(m.textInput.Value()); err != nil {
+			badges = append(badges, fmt.Sprintf("ignored %v", err))
+		}
 	}
 	if m.dep.aliveOnly {
 		badges = append(badges, "alive-only")
@@ -312,6 +381,12 @@ func (m model) depFilterBadges() []string {
 	if m.dep.portsOnly {
 		badges = append(badges, "listening-only")
 	}
+	if m.autoRefresh {
+		badges = append(badges, fmt.Sprintf("auto: %s", m.refreshInterval))
+	}
+	if n := len(m.selected); n > 0 {
+		badges = append(badges, fmt.Sprintf("%d selected", n))
+	}
 	return badges
 }
 
@@ -337,6 +412,7 @@ func (m model) renderProcessPane() string {
 	}
 
 	// --- 渲染视口内的每一行 ---
+	now := time.Now()
 	for i := start; i < end; i++ {
 		p := m.filtered[i]
 		status := " "
@@ -345,6 +421,16 @@ func (m model) renderProcessPane() string {
 			status = "K"
 		case process.Paused:
 			status = "P"
+		case process.Exited:
+			status = "X"
+		case process.Zombie:
+			status = "Z"
+		case process.Survived:
+			status = "!"
+		default:
+			if m.isRespawned(p) {
+				status = "R"
+			}
 		}
 
 		// Apply styles to individual columns
@@ -356,7 +442,7 @@ func (m model) renderProcessPane() string {
 		}
 
 		pidStr := pidStyle.Render(fmt.Sprintf("%d", p.Pid))
-		timeStr := timeStyle.Width(8).Render(p.StartTime)
+		timeStr := timeStyle.Width(10).Render(m.startColumn(p, now))
 		// Truncate the command to 20 characters to preserve layout
 		var displayName string
 		if p.ContainerName != "" {
@@ -372,11 +458,20 @@ func (m model) renderProcessPane() string {
 			cmdStr = commandStyle.Width(20).Render(truncatedCmd)
 		}
 
+		// 按 `c` 打开命令行列时，在名称后显示截断的参数，用于区分同名进程（如 `node server.js` 与 `node jest`）。
+		if m.showCmdline {
+			cmdStr += " " + argsStyle.Width(argsColumnWidth).Render(truncate(commandArgs(p), argsColumnWidth))
+		}
+
+		// 资源占用列：CPU%（相对单核，可超过 100）、常驻内存和线程数。
+		usageStr := usageStyle.Render(fmt.Sprintf("%5.1f %5s %3d", p.CPUPercent, compactBytes(p.RSS), p.Threads))
+
 		// Construct the line manually to preserve styles
-		// Format: [Status] Command StartTime User PID
-		line := fmt.Sprintf("[%s] %s %s %s %s",
+		// Format: [Status] Command CPU% RSS Threads Age/StartTime User PID
+		line := fmt.Sprintf("[%s] %s %s %s %s %s",
 			status,
 			cmdStr,
+			usageStr,
 			timeStr,
 			userStr,
 			pidStr,
@@ -387,21 +482,34 @@ func (m model) renderProcessPane() string {
 			line = killingStyle.Render(line)
 		case process.Paused:
 			line = pausedStyle.Render(line)
+		case process.Exited:
+			line = exitedStyle.Render(line)
+		case process.Zombie:
+			line = zombieStyle.Render(line)
+		case process.Survived:
+			line = survivedStyle.Render(line)
 		default:
-			if len(p.Ports) > 0 {
+			if m.isRespawned(p) {
+				line = respawnedStyle.Render(line)
+			} else if len(p.Ports) > 0 {
 				line = listeningStyle.Render(line)
 			}
 		}
 
+		mark := m.selectionMark(p)
 		if i == m.cursor {
-			fmt.Fprintln(&b, selectedStyle.Render("❯ "+line))
+			fmt.Fprintln(&b, selectedStyle.Render("❯ ")+mark+selectedStyle.Render(line))
 		} else {
-			fmt.Fprintln(&b, "  "+line)
+			fmt.Fprintln(&b, "  "+mark+line)
 		}
 	}
 
 	// 去掉末尾多余的换行，避免左侧列表底部出现空行。
-	return processPaneStyle.Render(strings.TrimRight(b.String(), "\n"))
+	pane := processPaneStyle
+	if m.showCmdline {
+		pane = pane.Width(processPaneStyle.GetWidth() + argsColumnWidth + 1)
+	}
+	return pane.Render(strings.TrimRight(b.String(), "\n"))
 }
 
 // truncate ensures a string does not exceed maxLen runes.
@@ -415,6 +523,48 @@ func truncate(s string, maxLen int) string {
 	return string(runes[:maxLen-1]) + "…"
 }
 
+// startColumn 渲染启动时间列：默认显示进程已运行的时长（如 "3d"、"5m"），
+// 按 `a` 切换为绝对时间（当天显示时刻，否则显示日期）。
+func (m model) startColumn(p *process.Item, now time.Time) string {
+	if m.absoluteStart || p.StartTime.IsZero() {
+		return process.FormatStartTime(p.StartTime, now)
+	}
+	return process.FormatAge(now.Sub(p.StartTime))
+}
+
+// argsColumnWidth 是命令行列的宽度。
+const argsColumnWidth = 32
+
+// commandArgs 返回命令行中可执行文件之后的部分；没有参数时返回完整命令行，读取不到时返回 "-"。
+func commandArgs(p *process.Item) string {
+	if p.Cmdline == "" {
+		return "-"
+	}
+	if _, args, ok := strings.Cut(p.Cmdline, " "); ok && args != "" {
+		return args
+	}
+	return p.Cmdline
+}
+
+// compactBytes 把字节数格式化为最多 5 个字符的紧凑形式（如 "512K"、"1.2G"），用于列表中的 RSS 列。
+func compactBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%dB", b)
+	}
+	value := float64(b) / unit
+	suffixes := "KMGTPE"
+	i := 0
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	if value < 10 {
+		return fmt.Sprintf("%.1f%c", value, suffixes[i])
+	}
+	return fmt.Sprintf("%.0f%c", value, suffixes[i])
+}
+
 // renderPortPane 负责渲染右侧的端口信息面板。
 func (m model) renderPortPane() string {
 	var b strings.Builder
@@ -465,12 +615,38 @@ func (m model) renderConfirmView() string {
 	title := confirmTitleStyle.Render("Confirm Action")
 	op := strings.Title(m.confirm.op)
 	var target string
-	if m.confirm.containerName != "" {
+	if m.confirm.pgid != 0 {
+		target = fmt.Sprintf("Process group %d of %s (%d), signalled with kill(-%d)\n", m.confirm.pgid, m.confirm.name, m.confirm.pid, m.confirm.pgid) +
+			renderBatchTargets("Known members", m.confirm.targets, m.confirmListHeight, m.confirmScroll)
+	} else if m.confirm.tree {
+		target = fmt.Sprintf("Subtree of %s (%d), children first\n", m.confirm.name, m.confirm.pid) +
+			renderBatchTargets("Processes", m.confirm.targets, m.confirmListHeight, m.confirmScroll)
+	} else if len(m.confirm.targets) > 0 {
+		target = renderBatchTargets("Targets", m.confirm.targets, m.confirmListHeight, m.confirmScroll)
+	} else if m.confirm.containerName != "" {
 		target = fmt.Sprintf("Container: %s", m.confirm.name)
 	} else {
 		target = fmt.Sprintf("Process: %s (%d)", m.confirm.name, m.confirm.pid)
 	}
 	msg := fmt.Sprintf("Action: %s\n%s", op, target)
+	if m.confirm.manager != nil {
+		msg += "\nCommand: " + m.confirm.manager.String()
+		msg += faintStyle.Render("\nThe manager stops the whole service, including any other processes it runs.")
+	} else if m.confirm.port == 0 && m.confirm.containerName == "" {
+		msg += "\nSignal: " + process.SignalName(m.confirm.sig)
+		if m.lastSignal != 0 && m.lastSignal != m.confirm.sig {
+			msg += faintStyle.Render(fmt.Sprintf(" (last picked with K: %s)", process.SignalName(m.lastSignal)))
+		}
+	}
+	if m.confirm.port != 0 {
+		listeners := make([]string, len(m.confirm.holders))
+		for i, h := range m.confirm.holders {
+			listeners[i] = fmt.Sprintf("%s (%d)", h.Executable, h.Pid)
+		}
+		msg += "\nListeners: " + strings.Join(listeners, ", ")
+		msg += fmt.Sprintf("\nEvery listener on port %d gets SIGTERM, then SIGKILL if it is still bound after a grace period.", m.confirm.port)
+		msg += faintStyle.Render("\nNothing is signalled if another process has bound the port since the last refresh.")
+	}
 	body := confirmPaneStyle.Render(confirmMessageStyle.Render(msg))
 	help := confirmHelpStyle.Render(" y/enter: confirm • n/esc: cancel • q: quit")
 	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, body, help))
@@ -484,10 +660,11 @@ func (m model) renderHelpView() string {
 	if m.showDetails {
 		fmt.Fprintln(&b, helpPaneStyle.Render(strings.Join([]string{
 			"Details view:",
+			"  tab/shift+tab or 1-7: Overview, Network, Files, Threads, Limits, Env, Cgroup",
 			"  scroll: up/down/pgup/pgdn",
-			"  v: toggle verbose mode",
-			"  e: toggle env section",
-			"  s: toggle env secrets (when env is on)",
+			"  L: live mode (sample CPU, RSS, FDs, threads and IO every second, drawn as sparklines)",
+			"  v: toggle verbose mode (Overview)",
+			"  e: jump to the Env tab • s: toggle env secrets",
 			"  esc: back • ?: close help",
 		}, "\n")))
 	} else if m.dep.mode {
@@ -496,29 +673,44 @@ func (m model) renderHelpView() string {
 			"  up/down (j/k): move cursor",
 			"  left/right/space (h/l/space): fold/unfold; on ‘… (deeper)’ drill deeper; on ‘… (N more)’ page",
 			"  enter/o: set current node as root; u: root up; a: toggle ancestors",
-			"  /: filter • S: alive-only • L: listening-only",
-			"  i: details • x: kill • p: pause • r: resume",
-			"  esc: back • ctrl+r: refresh • ?: close help",
+			"  /: filter (same terms as the main list, e.g. port:8080 cpu>50) • S: alive-only • L: listening-only",
+			"  i: details • x: kill • p: pause • r: resume • K: pick a signal (HUP, QUIT, USR1, …)",
+			"  X: kill the node and all of its descendants (leaves first) • G: kill the node's process group",
+			"  M: stop the node via its manager (systemctl, pm2, supervisorctl, docker)",
+			"  m: mark node • A: mark all visible (again to clear) • I: invert marks",
+			"  with marks, x/p/r apply to every marked process after one confirmation",
+			"  esc: back • ctrl+r: refresh • R: auto-refresh • ?: close help",
 		}, "\n")))
 	} else {
 		fmt.Fprintln(&b, helpPaneStyle.Render(strings.Join([]string{
 			"Main list:",
 			"  up/down (j/k): move cursor",
 			"  /: search • enter: kill • p: pause • r: resume • i: details",
-			"  P: ports-only • ctrl+r: refresh • T: dependency tree",
+			"  K: pick a signal to send (HUP to reload, QUIT for stack dumps, USR1/USR2, KILL, …)",
+			"  M: stop via the process's manager (systemctl, pm2, supervisorctl, docker) so it is not restarted",
+			"  R in the status column: restarted within 30s of a kill (the footer says by whom)",
+			"  search terms: user:root port:8080 pid:42 name:node container:api status:paused",
+			"                cpu>50 mem>1g age<10m (other words are matched fuzzily)",
+			"  space: mark row • A: mark all filtered (again to clear) • I: invert marks • esc: clear marks",
+			"  with marks, enter/p/r apply to every marked process after one confirmation",
+			"  F: free the selected process's port (TERM, then KILL, then wait until it closes)",
+			"  P: ports-only • ctrl+r: refresh • R: toggle auto-refresh • T: dependency tree",
+			"  s: cycle sort (cpu/mem/pid/start/user/port) • S: reverse sort",
+			"  a: toggle start column between age and start time • c: toggle command line column",
 			"  q/ctrl+c: quit • ?: close help",
 		}, "\n")))
 	}
 	return docStyle.Render(strings.TrimRight(b.String(), "\n"))
 }
 
-// formatProcessDetails 是一个辅助函数，它接收 `GetProcessDetails` 返回的原始详情字符串，
-// 并将其解析、格式化为一个美观的、带标签对齐且可自动换行的视图。
+// detailsSeparator 框住 "Why It Exists" 区块，与 `process.FormatDetails` 的文本布局一致。
+const detailsSeparator = "─────────────────────────────────────"
+
+// formatProcessDetails 将 `process.GetDetails` 返回的结构化详情渲染为一个美观的、
+// 带标签对齐且可自动换行的视图。各区块直接取自 Details 的字段，不需要解析文本。
 // viewportContentWidth 为 viewport 内容区域宽度（建议传入 viewport.Width 减去 viewport.Style 的水平 frame）。
-func formatProcessDetails(details string, viewportContentWidth int) string {
-	raw := strings.TrimRight(details, "\n")
-	lines := strings.Split(raw, "\n")
-	if len(lines) == 0 {
+func formatProcessDetails(d *process.Details, viewportContentWidth int) string {
+	if d == nil {
 		return faintStyle.Render("(no details)")
 	}
 
@@ -527,7 +719,12 @@ func formatProcessDetails(details string, viewportContentWidth int) string {
 		contentWidth = 80
 	}
 
-	labelWidth := computeDetailLabelWidth(lines, contentWidth)
+	base := d.BaseFields()
+	labels := fieldLabels(base, d.WhyFields(), d.ContextFields(), d.Verbose)
+	if d.Why != nil {
+		labels = append(labels, "Why It Exists")
+	}
+	labelWidth := computeDetailLabelWidth(labels, contentWidth)
 
 	formatter := detailFormatter{
 		labelWidth:       labelWidth,
@@ -536,8 +733,9 @@ func formatProcessDetails(details string, viewportContentWidth int) string {
 		valueWidth:       contentWidth - (labelWidth + 1),
 	}
 
-	for _, rawLine := range lines {
-		formatter.addLine(rawLine)
+	formatter.appendFields(base)
+	if d.Why != nil {
+		formatter.appendWhy(d)
 	}
 
 	return strings.Join(formatter.rows, "\n")
@@ -549,84 +747,59 @@ type detailFormatter struct {
 	valueColumnStart int
 	valueWidth       int
 	rows             []string
-	inWhy            bool
-	inWarnings       bool
 }
 
-func (f *detailFormatter) addLine(rawLine string) {
-	line := strings.TrimRight(rawLine, " \t\r")
-	if strings.TrimSpace(line) == "" {
-		f.rows = append(f.rows, "")
-		f.inWhy = false
-		f.inWarnings = false
-		return
-	}
-
-	trimmedLeft := strings.TrimLeft(line, " \t")
-	label, value := splitDetailLine(trimmedLeft)
-
-	if f.handleWhySection(label, value, trimmedLeft) {
-		return
-	}
-	if f.handleWarningsSection(label, value, trimmedLeft) {
-		return
+// appendWhy 渲染 "Why It Exists" 区块：祖先链、来源信息，以及 Context/Warnings/Verbose/Env 等可选小节。
+func (f *detailFormatter) appendWhy(d *process.Details) {
+	f.rows = append(f.rows, "")
+	f.appendPlainText(detailsSeparator)
+	f.appendSectionHeader("Why It Exists")
+	if len(d.Why.Ancestry) > 0 {
+		segments := make([]string, 0, len(d.Why.Ancestry))
+		for _, p := range d.Why.Ancestry {
+			segments = append(segments, fmt.Sprintf("%s (pid %d)", p.Command, p.PID))
+		}
+		f.rows = append(f.rows, formatWhyChain(segments, f.contentWidth, f.valueColumnStart)...)
 	}
+	f.rows = append(f.rows, "")
+	f.appendFields(d.WhyFields())
 
-	if label == "" {
-		f.appendPlainText(trimmedLeft)
-		return
+	if fields := d.ContextFields(); len(fields) > 0 {
+		f.appendSection("Context")
+		f.appendFields(fields)
 	}
-
-	f.appendKeyValue(label, value)
-}
-
-func (f *detailFormatter) handleWhySection(label, value, trimmedLeft string) bool {
-	if label == "Why It Exists" && strings.TrimSpace(value) == "" {
-		f.appendSectionHeader(label)
-		f.inWhy = true
-		return true
+	if len(d.Warnings) > 0 {
+		f.appendSection("Warnings")
+		for _, warning := range d.Warnings {
+			f.rows = append(f.rows, formatWarningLine(warning, f.contentWidth, f.valueColumnStart)...)
+		}
 	}
-
-	if !f.inWhy {
-		return false
+	if d.Verbose != nil {
+		f.appendSection("Verbose")
+		if len(d.Verbose) == 0 {
+			f.appendPlainText("(unavailable)")
+		}
+		f.appendFields(d.Verbose)
 	}
-
-	if label != "" && label != "Why It Exists" {
-		f.inWhy = false
-		return false
+	if d.Env != nil {
+		f.appendSection("Env")
+		for _, line := range d.Env.Lines() {
+			f.appendPlainText(line)
+		}
 	}
-
-	f.rows = append(f.rows, formatWhyChain(trimmedLeft, f.contentWidth, f.valueColumnStart)...)
-	return true
+	f.appendPlainText(detailsSeparator)
 }
 
-func (f *detailFormatter) handleWarningsSection(label, value, trimmedLeft string) bool {
-	if label == "Warnings" && strings.TrimSpace(value) == "" {
-		f.appendSectionHeader(label)
-		f.inWarnings = true
-		return true
-	}
-
-	if !f.inWarnings {
-		return false
-	}
-
-	trimmed := strings.TrimSpace(trimmedLeft)
-	if strings.HasPrefix(trimmed, "─") {
-		f.inWarnings = false
-		return false
-	}
-	if label != "" && label != "Warnings" {
-		f.inWarnings = false
-		return false
-	}
-
-	if label == "" {
-		f.rows = append(f.rows, formatWarningLine(trimmedLeft, f.contentWidth, f.valueColumnStart)...)
-		return true
+func (f *detailFormatter) appendFields(fields []process.DetailField) {
+	for _, field := range fields {
+		f.appendKeyValue(field.Label, field.Value)
 	}
+}
 
-	return false
+// appendSection 以空行隔开，并写入小节标题。
+func (f *detailFormatter) appendSection(label string) {
+	f.rows = append(f.rows, "")
+	f.appendSectionHeader(label)
 }
 
 func (f *detailFormatter) appendPlainText(line string) {
@@ -663,7 +836,18 @@ func (f *detailFormatter) appendSectionHeader(label string) {
 	f.rows = append(f.rows, lipgloss.JoinHorizontal(lipgloss.Top, labelCell, " ", ""))
 }
 
-func computeDetailLabelWidth(lines []string, contentWidth int) int {
+// fieldLabels 收集各组字段的标签，用于计算标签列宽度。
+func fieldLabels(groups ...[]process.DetailField) []string {
+	var labels []string
+	for _, fields := range groups {
+		for _, field := range fields {
+			labels = append(labels, field.Label)
+		}
+	}
+	return labels
+}
+
+func computeDetailLabelWidth(labels []string, contentWidth int) int {
 	// 标签列：最小 12，必要时增大，但不能挤占掉 value 列（至少留 1 列）。
 	maxPossible := contentWidth - 1
 	if maxPossible < 1 {
@@ -674,13 +858,7 @@ func computeDetailLabelWidth(lines []string, contentWidth int) int {
 	maxWidth := minInt(24, maxPossible)
 
 	width := minWidth
-	for _, rawLine := range lines {
-		line := strings.TrimRight(rawLine, " \t\r")
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		trimmedLeft := strings.TrimLeft(line, " \t")
-		label, _ := splitDetailLine(trimmedLeft)
+	for _, label := range labels {
 		if label == "" {
 			continue
 		}
@@ -699,9 +877,8 @@ func computeDetailLabelWidth(lines []string, contentWidth int) int {
 	return width
 }
 
-func formatWhyChain(chainLine string, contentWidth, valueColumnStart int) []string {
-	chain := strings.TrimSpace(chainLine)
-	if chain == "" {
+func formatWhyChain(segments []string, contentWidth, valueColumnStart int) []string {
+	if len(segments) == 0 {
 		return nil
 	}
 
@@ -710,11 +887,6 @@ func formatWhyChain(chainLine string, contentWidth, valueColumnStart int) []stri
 		valueWidth = 1
 	}
 
-	segments := splitWhySegments(chain)
-	if len(segments) == 0 {
-		segments = []string{chain}
-	}
-
 	formatter := newWhyChainFormatter(valueWidth, valueColumnStart)
 	formatter.appendSegments(segments)
 	return formatter.out
@@ -836,18 +1008,9 @@ func (f *whyChainFormatter) emitToken(prefix string, token string) {
 	}
 }
 
-func formatWarningLine(line string, contentWidth, valueColumnStart int) []string {
-	text := strings.TrimSpace(line)
-	if text == "" {
-		return []string{""}
-	}
-
-	icon := ""
-	message := text
-	if strings.HasPrefix(text, "⚠") {
-		icon = "⚠"
-		message = strings.TrimSpace(strings.TrimPrefix(text, "⚠"))
-	}
+// formatWarningLine 渲染一条警告：图标位于 value 列起始处，消息按剩余宽度换行并与图标后的文本对齐。
+func formatWarningLine(message string, contentWidth, valueColumnStart int) []string {
+	const icon = "⚠"
 
 	valueWidth := contentWidth - valueColumnStart
 	if valueWidth <= 0 {
@@ -855,13 +1018,9 @@ func formatWarningLine(line string, contentWidth, valueColumnStart int) []string
 	}
 
 	iconWidth := lipgloss.Width(icon)
-	gap := 0
-	if icon != "" {
-		gap = 1
-	}
 	textWidth := 1
-	if iconWidth+gap < valueWidth {
-		textWidth = valueWidth - iconWidth - gap
+	if iconWidth+1 < valueWidth {
+		textWidth = valueWidth - iconWidth - 1
 	}
 
 	wrapped := wrapPlainText(message, textWidth)
@@ -870,25 +1029,10 @@ func formatWarningLine(line string, contentWidth, valueColumnStart int) []string
 	}
 
 	basePrefix := strings.Repeat(" ", valueColumnStart)
-	var out []string
-
-	if icon != "" {
-		line0 := basePrefix + warningStyle.Render(icon)
-		if gap > 0 {
-			line0 += " "
-		}
-		line0 += detailValueStyle.Render(wrapped[0])
-		out = append(out, line0)
-
-		contPrefix := basePrefix + strings.Repeat(" ", iconWidth+gap)
-		for _, part := range wrapped[1:] {
-			out = append(out, contPrefix+detailValueStyle.Render(part))
-		}
-		return out
-	}
-
-	for _, part := range wrapped {
-		out = append(out, basePrefix+detailValueStyle.Render(part))
+	out := []string{basePrefix + warningStyle.Render(icon) + " " + detailValueStyle.Render(wrapped[0])}
+	contPrefix := basePrefix + strings.Repeat(" ", iconWidth+1)
+	for _, part := range wrapped[1:] {
+		out = append(out, contPrefix+detailValueStyle.Render(part))
 	}
 	return out
 }
@@ -940,21 +1084,6 @@ func detailValueStyleFor(label, value string) lipgloss.Style {
 	}
 }
 
-func splitWhySegments(chain string) []string {
-	// 允许 `a → b → c` 与 `a→b→c` 两种格式。
-	if strings.Contains(chain, "→") {
-		parts := strings.Split(chain, "→")
-		out := make([]string, 0, len(parts))
-		for _, p := range parts {
-			if s := strings.TrimSpace(p); s != "" {
-				out = append(out, s)
-			}
-		}
-		return out
-	}
-	return []string{chain}
-}
-
 func wrapPlainText(text string, width int) []string {
 	txt := strings.TrimSpace(text)
 	if txt == "" {
@@ -1045,18 +1174,6 @@ func minInt(a, b int) int {
 	return b
 }
 
-// splitDetailLine 是一个健壮的辅助函数，用于将详情行分割为标签和值。
-// 它能处理 ":\t" 和 ":" 两种分隔符。
-func splitDetailLine(line string) (string, string) {
-	if idx := strings.Index(line, ":\t"); idx != -1 {
-		return strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+2:])
-	}
-	if idx := strings.Index(line, ":"); idx != -1 {
-		return strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:])
-	}
-	return "", line
-}
-
 // friendlyErrorMessage 函数接收一个原始的 `error`，并尝试将其转换为一个对用户更友好的消息。
 // 它通过匹配错误字符串中的常见模式（如权限问题、进程不存在等），来附加一些有用的提示信息。
 func friendlyErrorMessage(err error) string {
@@ -1068,6 +1185,10 @@ func friendlyErrorMessage(err error) string {
 	lower := strings.ToLower(raw)
 
 	switch {
+	case errors.Is(err, process.ErrPIDReused):
+		return fmt.Sprintf("%s\n\nHint: The process exited and its PID now belongs to another process, so nothing was sent. Refresh the list (ctrl+r).", raw)
+	case errors.Is(err, process.ErrHoldersChanged):
+		return fmt.Sprintf("%s\n\nHint: Another process bound the port after the list was loaded, so nothing was sent. Refresh the list (ctrl+r) and check the listeners again.", raw)
 	case strings.Contains(lower, "operation not permitted") || strings.Contains(lower, "permission denied"):
 		return fmt.Sprintf("%s\n\nHint: Try running gokill with sudo or as an administrator.", raw)
 	case strings.Contains(lower, "not found") || strings.Contains(lower, "no such process"):
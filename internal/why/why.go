@@ -10,16 +10,16 @@ import (
 
 // ProcessInfo contains information about a single process in the ancestry chain.
 type ProcessInfo struct {
-	PID        int           // Process ID
-	PPID       int           // Parent Process ID
-	Command    string        // Short command name (e.g., "node")
-	Cmdline    string        // Full command line
-	User       string        // Username running the process
-	StartedAt  time.Time     // Process start time
-	WorkingDir string        // Current working directory
-	Status     string        // Process status (R, S, Z, etc.)
-	RSS        uint64        // Resident Set Size (bytes)
-	CPUTime    time.Duration // Total CPU time consumed (best-effort)
+	PID        int           `json:"pid"`                  // Process ID
+	PPID       int           `json:"ppid"`                 // Parent Process ID
+	Command    string        `json:"command"`              // Short command name (e.g., "node")
+	Cmdline    string        `json:"cmdline,omitempty"`    // Full command line
+	User       string        `json:"user,omitempty"`       // Username running the process
+	StartedAt  time.Time     `json:"startedAt"`            // Process start time
+	WorkingDir string        `json:"workingDir,omitempty"` // Current working directory
+	Status     string        `json:"status,omitempty"`     // Process status (R, S, Z, etc.)
+	RSS        uint64        `json:"rss,omitempty"`        // Resident Set Size (bytes)
+	CPUTime    time.Duration `json:"cpuTime,omitempty"`    // Total CPU time consumed in nanoseconds (best-effort)
 }
 
 // SourceType represents the type of process supervisor or launcher.
@@ -38,25 +38,25 @@ const (
 
 // Source represents the detected origin/supervisor of a process.
 type Source struct {
-	Type       SourceType // The type of source (systemd, launchd, etc.)
-	Name       string     // Service/unit name if available
-	Confidence float64    // Confidence score 0.0-1.0
+	Type       SourceType `json:"type"`           // The type of source (systemd, launchd, etc.)
+	Name       string     `json:"name,omitempty"` // Service/unit name if available
+	Confidence float64    `json:"confidence"`     // Confidence score 0.0-1.0
 }
 
 // AnalysisResult contains the complete analysis of why a process is running.
 type AnalysisResult struct {
-	Ancestry     []ProcessInfo // Process chain from init to target
-	Source       Source        // Detected process source/supervisor
-	WorkingDir   string        // Working directory of target process
-	GitRepo      string        // Git repository name (if applicable)
-	GitBranch    string        // Git branch (if applicable)
-	SystemdUnit  string        // systemd unit name (best-effort, Linux-only)
-	Env          []string      // Environment variables (key=value, best-effort)
-	EnvError     string        // Environment read error (best-effort)
-	ExeDeleted   bool          // True if executable is deleted (best-effort)
-	RestartCount int           // Consecutive restart count (best-effort)
-	ContainerID  string        // Container identifier (best-effort)
-	Warnings     []string      // Health/security warnings
+	Ancestry     []ProcessInfo `json:"ancestry"`              // Process chain from init to target
+	Source       Source        `json:"source"`                // Detected process source/supervisor
+	WorkingDir   string        `json:"workingDir,omitempty"`  // Working directory of target process
+	GitRepo      string        `json:"gitRepo,omitempty"`     // Git repository name (if applicable)
+	GitBranch    string        `json:"gitBranch,omitempty"`   // Git branch (if applicable)
+	SystemdUnit  string        `json:"systemdUnit,omitempty"` // systemd unit name (best-effort, Linux-only)
+	Env          []string      `json:"env,omitempty"`         // Environment variables (key=value, best-effort)
+	EnvError     string        `json:"envError,omitempty"`    // Environment read error (best-effort)
+	ExeDeleted   bool          `json:"exeDeleted"`            // True if executable is deleted (best-effort)
+	RestartCount int           `json:"restartCount"`          // Consecutive restart count (best-effort)
+	ContainerID  string        `json:"containerId,omitempty"` // Container identifier (best-effort)
+	Warnings     []string      `json:"warnings"`              // Health/security warnings
 }
 
 // Analyzer provides process ancestry analysis.